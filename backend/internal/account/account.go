@@ -1,10 +1,12 @@
 package account
 
-import (
-	gonanoid "github.com/matoous/go-nanoid/v2"
-)
+import "context"
 
-const codeAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+// Roles an Account can have.
+const (
+	RoleTeacher = "teacher"
+	RoleStudent = "student"
+)
 
 type Account interface {
 	ID() string
@@ -36,30 +38,55 @@ func (a account) Role() string {
 	return a.role
 }
 
-func NewTeacher(name string) Account {
-	return account{
-		name: name,
-		role: "teacher",
-		id:   gonanoid.Must(),
-		code: newAccountCode(),
-	}
+// New returns an account with the given details, as a Store holds it.
+// Use NewTeacher, NewStudent or a CodeGenerator to make accounts for new
+// users.
+func New(id string, name string, role string, code string) Account {
+	return account{id: id, name: name, role: role, code: code}
 }
 
-func newAccountCode() string {
-	return gonanoid.MustGenerate(codeAlphabet, 4)
+// NewTeacher and NewStudent make accounts with a code from the default
+// CodeGenerator, which may already be in use. Use a CodeGenerator to
+// create accounts with codes which are checked against a Store.
+func NewTeacher(name string) Account {
+	return defaultCodes.newAccount(name, RoleTeacher)
 }
 
 func NewStudent(name string) Account {
-	return account{
-		name: name,
-		role: "student",
-		id:   gonanoid.Must(),
-		code: newAccountCode(),
-	}
+	return defaultCodes.newAccount(name, RoleStudent)
 }
 
+// Store keeps accounts, looked up by id or login code. It may be used
+// from many goroutines at once. Every call leaves it consistent, so no
+// two accounts ever share a code however saves and code changes
+// interleave, but nothing is held between calls.
+//
+// Every method takes the context of the request it serves and gives up
+// with the context's error once it is cancelled. Accounts that do not
+// exist are reported with errors matching ErrNotFound, and codes which
+// are already in use with errors matching ErrConflict.
 type Store interface {
-	SaveAccount(account Account) error
-	Login(code string) (Account, error)
-	AccountExists(id string) bool
+	SaveAccount(ctx context.Context, account Account) error
+	// SaveAccounts saves all of accounts or, on any error, none of
+	// them. A code used twice, or already in use, is a CodeConflictError.
+	SaveAccounts(ctx context.Context, accounts []Account) error
+	Login(ctx context.Context, code string) (Account, error)
+	AccountExists(ctx context.Context, id string) (bool, error)
+	GetAccount(ctx context.Context, id string) (Account, error)
+	GetAccounts(ctx context.Context, role string) ([]Account, error)
+	// RenameAccount and DeleteAccount return an *AccountDoesNotExistError
+	// for unknown ids.
+	RenameAccount(ctx context.Context, id string, name string) error
+	DeleteAccount(ctx context.Context, id string) error
+	// ChangeCode replaces the account's login code, returning the
+	// updated account, or a CodeConflictError if another account uses
+	// code. The old code stops working straight away.
+	ChangeCode(ctx context.Context, id string, code string) (Account, error)
+	CountAccounts(ctx context.Context) (int, error)
+	// SetPasswordHash stores hash as the account's password, or
+	// removes its password if hash is nil. PasswordHash returns nil for
+	// an account without one. Use SetPassword and CheckPassword rather
+	// than hashing passwords yourself.
+	SetPasswordHash(ctx context.Context, id string, hash []byte) error
+	PasswordHash(ctx context.Context, id string) ([]byte, error)
 }
@@ -0,0 +1,199 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// CodeOptions configure a CodeGenerator.
+type CodeOptions struct {
+	Length   int
+	Alphabet string
+	// ExcludeAmbiguous leaves out characters which are easily mistaken
+	// for one another when read off a printed card: 0 and O, 1 and I.
+	ExcludeAmbiguous bool
+	// Attempts is how many codes are tried for an account before giving
+	// up with a CodeConflictError.
+	Attempts int
+}
+
+// DefaultCodeOptions make the 4 character codes students have always
+// used.
+var DefaultCodeOptions = CodeOptions{
+	Length:   4,
+	Alphabet: "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	Attempts: 20,
+}
+
+const ambiguousCharacters = "0O1I"
+
+var defaultCodes = mustCodeGenerator(DefaultCodeOptions)
+
+// CodeGenerator makes random login codes, retrying against a Store
+// until it finds one no other account uses.
+type CodeGenerator struct {
+	length   int
+	alphabet string
+	attempts int
+}
+
+func NewCodeGenerator(opts CodeOptions) (*CodeGenerator, error) {
+	var alphabet strings.Builder
+	for _, r := range opts.Alphabet {
+		if strings.ContainsRune(alphabet.String(), r) {
+			continue
+		}
+		if opts.ExcludeAmbiguous && strings.ContainsRune(ambiguousCharacters, r) {
+			continue
+		}
+		alphabet.WriteRune(r)
+	}
+	switch {
+	case opts.Length < 1:
+		return nil, errors.New("account: login codes must be at least 1 character long")
+	case len([]rune(alphabet.String())) < 2:
+		return nil, errors.New("account: login codes need an alphabet of at least 2 characters")
+	case opts.Attempts < 1:
+		return nil, errors.New("account: login codes need at least 1 attempt")
+	}
+	return &CodeGenerator{
+		length:   opts.Length,
+		alphabet: alphabet.String(),
+		attempts: opts.Attempts,
+	}, nil
+}
+
+func mustCodeGenerator(opts CodeOptions) *CodeGenerator {
+	g, err := NewCodeGenerator(opts)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// Generate returns a random code, which may already be in use.
+func (g *CodeGenerator) Generate() string {
+	return gonanoid.MustGenerate(g.alphabet, g.length)
+}
+
+func (g *CodeGenerator) newAccount(name string, role string) Account {
+	return account{
+		name: name,
+		role: role,
+		id:   gonanoid.Must(),
+		code: g.Generate(),
+	}
+}
+
+// CreateAccount saves a new account with a code no other account uses.
+func (g *CodeGenerator) CreateAccount(ctx context.Context, store Store, name string, role string) (Account, error) {
+	var err error
+	for i := 0; i < g.attempts; i++ {
+		acc := g.newAccount(name, role)
+		err = store.SaveAccount(ctx, acc)
+		if !errors.Is(err, ErrConflict) {
+			return acc, err
+		}
+	}
+	return nil, err
+}
+
+// CreateAccounts saves a new account for each name in a single call to
+// store.SaveAccounts, so either all are created or none are. The
+// accounts are returned in the same order as names. No two of them are
+// given the same code, and when a code turns out to be in use already
+// only that account is given another.
+func (g *CodeGenerator) CreateAccounts(ctx context.Context, store Store, names []string, role string) ([]Account, error) {
+	accounts := make([]Account, len(names))
+	tries := make([]int, len(names))
+	// batch maps the codes tried so far to the account using them, or
+	// to -1 for codes found to be in use already.
+	batch := make(map[string]int, len(names))
+	assign := func(j int, id string) error {
+		var code string
+		for tries[j] < g.attempts {
+			tries[j]++
+			code = g.Generate()
+			if _, ok := batch[code]; ok {
+				continue
+			}
+			batch[code] = j
+			accounts[j] = account{name: names[j], role: role, id: id, code: code}
+			return nil
+		}
+		return CodeConflictError{code: code}
+	}
+	for j := range names {
+		if err := assign(j, gonanoid.Must()); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		err := store.SaveAccounts(ctx, accounts)
+		if err == nil {
+			return accounts, nil
+		}
+		var conflict CodeConflictError
+		if !errors.As(err, &conflict) {
+			return nil, err
+		}
+		j, ok := batch[conflict.code]
+		if !ok || j < 0 {
+			return nil, err
+		}
+		batch[conflict.code] = -1
+		if err := assign(j, accounts[j].ID()); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// RegenerateCode gives the account a new code which no account, itself
+// included, uses, and returns the updated account.
+func (g *CodeGenerator) RegenerateCode(ctx context.Context, store Store, id string) (Account, error) {
+	acc, err := store.GetAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < g.attempts; i++ {
+		code := g.Generate()
+		if code == acc.Code() {
+			err = CodeConflictError{code: code}
+			continue
+		}
+		var updated Account
+		updated, err = store.ChangeCode(ctx, id, code)
+		if !errors.Is(err, ErrConflict) {
+			return updated, err
+		}
+	}
+	return nil, err
+}
+
+// CodeUsage reports how full the space of possible codes is.
+type CodeUsage struct {
+	// Used is how many accounts have a code.
+	Used int `json:"used"`
+	// Total is how many different codes can be generated.
+	Total float64 `json:"total"`
+	// Fullness is the fraction of Total in use, which is also the
+	// chance that a freshly generated code has to be retried.
+	Fullness float64 `json:"fullness"`
+}
+
+func (g *CodeGenerator) Usage(ctx context.Context, store Store) (CodeUsage, error) {
+	used, err := store.CountAccounts(ctx)
+	if err != nil {
+		return CodeUsage{}, err
+	}
+	total := math.Pow(float64(len([]rune(g.alphabet))), float64(g.length))
+	return CodeUsage{
+		Used:     used,
+		Total:    total,
+		Fullness: float64(used) / total,
+	}, nil
+}
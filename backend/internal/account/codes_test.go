@@ -0,0 +1,141 @@
+package account
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCodeGenerator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should make codes of the configured length from the alphabet", func(t *testing.T) {
+		g, err := NewCodeGenerator(CodeOptions{Length: 6, Alphabet: "ABC", Attempts: 1})
+		require.NoError(t, err)
+		for i := 0; i < 20; i++ {
+			code := g.Generate()
+			assert.Len(t, code, 6)
+			assert.Empty(t, strings.Trim(code, "ABC"), code)
+		}
+	})
+
+	t.Run("should leave out ambiguous characters", func(t *testing.T) {
+		opts := DefaultCodeOptions
+		opts.ExcludeAmbiguous = true
+		g, err := NewCodeGenerator(opts)
+		require.NoError(t, err)
+		for i := 0; i < 200; i++ {
+			code := g.Generate()
+			assert.False(t, strings.ContainsAny(code, ambiguousCharacters), code)
+		}
+	})
+
+	t.Run("should refuse options which cannot make codes", func(t *testing.T) {
+		for _, opts := range []CodeOptions{
+			{Length: 0, Alphabet: "AB", Attempts: 1},
+			{Length: 4, Alphabet: "AAAA", Attempts: 1},
+			{Length: 4, Alphabet: "0O", ExcludeAmbiguous: true, Attempts: 1},
+			{Length: 4, Alphabet: "AB", Attempts: 0},
+		} {
+			_, err := NewCodeGenerator(opts)
+			assert.Error(t, err, "%+v", opts)
+		}
+	})
+}
+
+func TestCodeGeneratorRetries(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, store Store) {
+		// With only two possible codes, the generator has to retry to
+		// find the free one.
+		g, err := NewCodeGenerator(CodeOptions{Length: 1, Alphabet: "AB", Attempts: 100})
+		require.NoError(t, err)
+
+		first, err := g.CreateAccount(context.Background(), store, "Student A", RoleStudent)
+		require.NoError(t, err)
+		second, err := g.CreateAccount(context.Background(), store, "Student B", RoleStudent)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"A", "B"}, []string{first.Code(), second.Code()})
+
+		_, err = g.CreateAccount(context.Background(), store, "Student C", RoleStudent)
+		assert.ErrorIs(t, err, ErrConflict, "a full code space should be reported as a conflict")
+		_, err = g.CreateAccounts(context.Background(), store, []string{"Student C"}, RoleStudent)
+		assert.ErrorIs(t, err, ErrConflict)
+		_, err = g.RegenerateCode(context.Background(), store, first.ID())
+		assert.ErrorIs(t, err, ErrConflict)
+
+		usage, err := g.Usage(context.Background(), store)
+		require.NoError(t, err)
+		assert.Equal(t, CodeUsage{Used: 2, Total: 2, Fullness: 1}, usage)
+	})
+}
+
+func TestCodeGeneratorRegenerateCode(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, store Store) {
+		g, err := NewCodeGenerator(CodeOptions{Length: 1, Alphabet: "ABC", Attempts: 100})
+		require.NoError(t, err)
+		a, err := g.CreateAccount(context.Background(), store, "Student A", RoleStudent)
+		require.NoError(t, err)
+		b, err := g.CreateAccount(context.Background(), store, "Student B", RoleStudent)
+		require.NoError(t, err)
+
+		updated, err := g.RegenerateCode(context.Background(), store, a.ID())
+		require.NoError(t, err)
+		assert.NotEqual(t, a.Code(), updated.Code())
+		assert.NotEqual(t, b.Code(), updated.Code())
+
+		_, err = g.RegenerateCode(context.Background(), store, "this-doesn't-exist")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+// batchRecorder records the codes of each batch of accounts saved.
+type batchRecorder struct {
+	Store
+	batches [][]string
+}
+
+func (r *batchRecorder) SaveAccounts(ctx context.Context, accounts []Account) error {
+	codes := make([]string, len(accounts))
+	for i, acc := range accounts {
+		codes[i] = acc.Code()
+	}
+	r.batches = append(r.batches, codes)
+	return r.Store.SaveAccounts(ctx, accounts)
+}
+
+func TestCodeGeneratorCreateAccounts(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, store Store) {
+		g, err := NewCodeGenerator(CodeOptions{Length: 1, Alphabet: "ABCD", Attempts: 100})
+		require.NoError(t, err)
+		for _, code := range []string{"A", "B"} {
+			require.NoError(t, store.SaveAccount(context.Background(), New("taken-"+code, "Taken "+code, RoleStudent, code)))
+		}
+		recorder := &batchRecorder{Store: store}
+
+		created, err := g.CreateAccounts(context.Background(), recorder, []string{"Student C", "Student D"}, RoleStudent)
+		require.NoError(t, err)
+		require.Len(t, created, 2)
+		assert.Equal(t, "Student C", created[0].Name())
+		assert.Equal(t, "Student D", created[1].Name())
+		assert.ElementsMatch(t, []string{"C", "D"}, []string{created[0].Code(), created[1].Code()})
+
+		for i, batch := range recorder.batches {
+			assert.NotEqual(t, batch[0], batch[1], "codes should be unique within a batch")
+			if i > 0 {
+				changed := 0
+				for j := range batch {
+					if batch[j] != recorder.batches[i-1][j] {
+						changed++
+					}
+				}
+				assert.Equal(t, 1, changed, "only the conflicting code should be generated again")
+			}
+		}
+	})
+}
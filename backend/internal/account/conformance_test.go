@@ -0,0 +1,27 @@
+package account_test
+
+import (
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/database"
+	"github.com/Manchester-Dev/medlock/internal/storetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryStore(t *testing.T) {
+	storetest.TestAccountStore(t, func(t *testing.T) account.Store {
+		return account.NewInMemoryStore()
+	})
+}
+
+func TestSQLiteStore(t *testing.T) {
+	storetest.TestAccountStore(t, func(t *testing.T) account.Store {
+		db, err := database.Open(":memory:")
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		store, err := account.NewSQLiteStore(db)
+		require.NoError(t, err)
+		return store
+	})
+}
@@ -1,16 +1,34 @@
 package account
 
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
 func NewInMemoryStore() Store {
 	return &inmemory{
-		accounts: make(map[string]Account),
+		accounts:  make(map[string]Account),
+		passwords: make(map[string][]byte),
 	}
 }
 
 type inmemory struct {
-	accounts map[string]Account
+	mu        sync.RWMutex
+	accounts  map[string]Account
+	passwords map[string][]byte
 }
 
-func (i *inmemory) AccountExists(id string) bool {
+func (i *inmemory) AccountExists(ctx context.Context, id string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.accountExists(id), nil
+}
+
+func (i *inmemory) accountExists(id string) bool {
 	for _, acc := range i.accounts {
 		if acc.ID() != id {
 			continue
@@ -20,6 +38,45 @@ func (i *inmemory) AccountExists(id string) bool {
 	return false
 }
 
+func (i *inmemory) GetAccount(ctx context.Context, id string) (Account, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for _, acc := range i.accounts {
+		if acc.ID() == id {
+			return acc, nil
+		}
+	}
+	return nil, &AccountDoesNotExistError{id: id}
+}
+
+func (i *inmemory) GetAccounts(ctx context.Context, role string) ([]Account, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	var accounts []Account
+	for _, acc := range i.accounts {
+		if acc.Role() != role {
+			continue
+		}
+		accounts = append(accounts, acc)
+	}
+	return accounts, nil
+}
+
+// ErrNotFound and ErrConflict are the kinds of error a Store returns,
+// for use with errors.Is. An *AccountDoesNotExistError or
+// *CodeDoesNotExistError matches ErrNotFound, and a CodeConflictError
+// matches ErrConflict.
+var (
+	ErrNotFound = errors.New("account not found")
+	ErrConflict = errors.New("account conflict")
+)
+
 type CodeConflictError struct {
 	code string
 }
@@ -32,11 +89,36 @@ func (e *CodeDoesNotExistError) Error() string {
 	return "Account Does not exist " + e.code
 }
 
+func (e *CodeDoesNotExistError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+type AccountDoesNotExistError struct {
+	id string
+}
+
+func (e *AccountDoesNotExistError) Error() string {
+	return "no account with id " + e.id
+}
+
+func (e *AccountDoesNotExistError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
 func (e CodeConflictError) Error() string {
 	return "account already exists with code " + e.code
 }
 
-func (i *inmemory) SaveAccount(account Account) error {
+func (e CodeConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+func (i *inmemory) SaveAccount(ctx context.Context, account Account) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
 	if _, ok := i.accounts[account.Code()]; ok {
 		return CodeConflictError{code: account.Code()}
 	}
@@ -44,7 +126,136 @@ func (i *inmemory) SaveAccount(account Account) error {
 	return nil
 }
 
-func (i *inmemory) Login(code string) (Account, error) {
+func (i *inmemory) SaveAccounts(ctx context.Context, accounts []Account) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	codes := make(map[string]bool, len(accounts))
+	for _, acc := range accounts {
+		if _, ok := i.accounts[acc.Code()]; ok || codes[acc.Code()] {
+			return CodeConflictError{code: acc.Code()}
+		}
+		codes[acc.Code()] = true
+	}
+	for _, acc := range accounts {
+		i.accounts[acc.Code()] = acc
+	}
+	return nil
+}
+
+func (i *inmemory) RenameAccount(ctx context.Context, id string, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for code, acc := range i.accounts {
+		if acc.ID() != id {
+			continue
+		}
+		i.accounts[code] = account{
+			id:   acc.ID(),
+			name: name,
+			role: acc.Role(),
+			code: acc.Code(),
+		}
+		return nil
+	}
+	return &AccountDoesNotExistError{id: id}
+}
+
+func (i *inmemory) ChangeCode(ctx context.Context, id string, code string) (Account, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for old, acc := range i.accounts {
+		if acc.ID() != id {
+			continue
+		}
+		if other, ok := i.accounts[code]; ok && other.ID() != id {
+			return nil, CodeConflictError{code: code}
+		}
+		updated := account{
+			id:   acc.ID(),
+			name: acc.Name(),
+			role: acc.Role(),
+			code: code,
+		}
+		delete(i.accounts, old)
+		i.accounts[code] = updated
+		return updated, nil
+	}
+	return nil, &AccountDoesNotExistError{id: id}
+}
+
+func (i *inmemory) CountAccounts(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.accounts), nil
+}
+
+func (i *inmemory) SetPasswordHash(ctx context.Context, id string, hash []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if !i.accountExists(id) {
+		return &AccountDoesNotExistError{id: id}
+	}
+	if hash == nil {
+		delete(i.passwords, id)
+		return nil
+	}
+	i.passwords[id] = append([]byte(nil), hash...)
+	return nil
+}
+
+func (i *inmemory) PasswordHash(ctx context.Context, id string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if !i.accountExists(id) {
+		return nil, &AccountDoesNotExistError{id: id}
+	}
+	hash, ok := i.passwords[id]
+	if !ok {
+		return nil, nil
+	}
+	return append([]byte(nil), hash...), nil
+}
+
+func (i *inmemory) DeleteAccount(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for code, acc := range i.accounts {
+		if acc.ID() == id {
+			delete(i.accounts, code)
+			delete(i.passwords, id)
+			return nil
+		}
+	}
+	return &AccountDoesNotExistError{id: id}
+}
+
+func (i *inmemory) Login(ctx context.Context, code string) (Account, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
 	stud, ok := i.accounts[code]
 	if !ok {
 		return nil, &CodeDoesNotExistError{code: code}
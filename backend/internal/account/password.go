@@ -0,0 +1,66 @@
+package account
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Passwords are only checked for teachers, as a second factor on top of
+// their login code. Students log in with their code alone.
+const (
+	MinPasswordLength = 8
+	// MaxPasswordLength is the most bcrypt will hash; anything longer
+	// would be silently truncated.
+	MaxPasswordLength = 72
+)
+
+var (
+	ErrPasswordTooShort = errors.New("password is too short")
+	ErrPasswordTooLong  = errors.New("password is too long")
+)
+
+// passwordCost is the bcrypt cost passwords are hashed with. Tests lower
+// it to keep hashing quick.
+var passwordCost = bcrypt.DefaultCost
+
+// SetPassword hashes password and stores it for the account with id.
+func SetPassword(ctx context.Context, store Store, id string, password string) error {
+	if len(password) < MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+	if len(password) > MaxPasswordLength {
+		return ErrPasswordTooLong
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordCost)
+	if err != nil {
+		return err
+	}
+	return store.SetPasswordHash(ctx, id, hash)
+}
+
+// RemovePassword lets the account with id log in with its code alone.
+func RemovePassword(ctx context.Context, store Store, id string) error {
+	return store.SetPasswordHash(ctx, id, nil)
+}
+
+// HasPassword reports whether the account with id has a password set.
+func HasPassword(ctx context.Context, store Store, id string) (bool, error) {
+	hash, err := store.PasswordHash(ctx, id)
+	return hash != nil, err
+}
+
+// CheckPassword reports whether password is the one set for the account
+// with id. An account without a password accepts none.
+func CheckPassword(ctx context.Context, store Store, id string, password string) (bool, error) {
+	hash, err := store.PasswordHash(ctx, id)
+	if err != nil || hash == nil {
+		return false, err
+	}
+	err = bcrypt.CompareHashAndPassword(hash, []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return err == nil, err
+}
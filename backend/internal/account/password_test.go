@@ -0,0 +1,65 @@
+package account
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func init() {
+	passwordCost = bcrypt.MinCost
+}
+
+func TestPasswords(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, store Store) {
+		teacher := NewTeacher("Teacher A")
+		require.NoError(t, store.SaveAccount(context.Background(), teacher))
+
+		t.Run("should accept no password before one is set", func(t *testing.T) {
+			has, err := HasPassword(context.Background(), store, teacher.ID())
+			require.NoError(t, err)
+			assert.False(t, has)
+			ok, err := CheckPassword(context.Background(), store, teacher.ID(), "")
+			require.NoError(t, err)
+			assert.False(t, ok)
+		})
+
+		t.Run("should only accept the password that was set", func(t *testing.T) {
+			require.NoError(t, SetPassword(context.Background(), store, teacher.ID(), "correct horse"))
+			has, err := HasPassword(context.Background(), store, teacher.ID())
+			require.NoError(t, err)
+			assert.True(t, has)
+
+			hash, err := store.PasswordHash(context.Background(), teacher.ID())
+			require.NoError(t, err)
+			assert.NotContains(t, string(hash), "correct horse")
+
+			ok, err := CheckPassword(context.Background(), store, teacher.ID(), "correct horse")
+			require.NoError(t, err)
+			assert.True(t, ok)
+			ok, err = CheckPassword(context.Background(), store, teacher.ID(), "battery staple")
+			require.NoError(t, err)
+			assert.False(t, ok)
+		})
+
+		t.Run("should refuse passwords of the wrong length", func(t *testing.T) {
+			assert.Equal(t, ErrPasswordTooShort, SetPassword(context.Background(), store, teacher.ID(), "short"))
+			assert.Equal(t, ErrPasswordTooLong, SetPassword(context.Background(), store, teacher.ID(), strings.Repeat("a", MaxPasswordLength+1)))
+			ok, err := CheckPassword(context.Background(), store, teacher.ID(), "correct horse")
+			require.NoError(t, err)
+			assert.True(t, ok, "the old password should be kept")
+		})
+
+		t.Run("should accept no password once removed", func(t *testing.T) {
+			require.NoError(t, RemovePassword(context.Background(), store, teacher.ID()))
+			has, err := HasPassword(context.Background(), store, teacher.ID())
+			require.NoError(t, err)
+			assert.False(t, has)
+		})
+	})
+}
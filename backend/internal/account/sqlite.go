@@ -0,0 +1,200 @@
+package account
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Manchester-Dev/medlock/internal/database"
+)
+
+var migrations = []string{
+	`CREATE TABLE accounts (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		role TEXT NOT NULL,
+		code TEXT NOT NULL
+	);
+	CREATE UNIQUE INDEX accounts_by_code ON accounts (code);`,
+	`ALTER TABLE accounts ADD COLUMN password_hash BLOB;`,
+}
+
+// NewSQLiteStore returns a Store persisted in db, so accounts and their
+// login codes survive restarts. The schema is migrated to the latest
+// version first.
+func NewSQLiteStore(db *sql.DB) (Store, error) {
+	if err := database.Migrate(db, "accounts", migrations); err != nil {
+		return nil, err
+	}
+	return &sqlite{db: db}, nil
+}
+
+type sqlite struct {
+	db *sql.DB
+}
+
+func (s *sqlite) SaveAccount(ctx context.Context, acc Account) error {
+	return saveAccount(ctx, s.db, acc)
+}
+
+func (s *sqlite) SaveAccounts(ctx context.Context, accounts []Account) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, acc := range accounts {
+		if err := saveAccount(ctx, tx, acc); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func saveAccount(ctx context.Context, db execer, acc Account) error {
+	res, err := db.ExecContext(ctx, `INSERT INTO accounts (id, name, role, code) VALUES (?, ?, ?, ?)
+		ON CONFLICT (code) DO NOTHING`,
+		acc.ID(), acc.Name(), acc.Role(), acc.Code())
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return CodeConflictError{code: acc.Code()}
+	}
+	return nil
+}
+
+func (s *sqlite) Login(ctx context.Context, code string) (Account, error) {
+	var acc account
+	err := s.db.QueryRowContext(ctx, `SELECT id, name, role, code FROM accounts WHERE code = ?`, code).
+		Scan(&acc.id, &acc.name, &acc.role, &acc.code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &CodeDoesNotExistError{code: code}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+func (s *sqlite) GetAccount(ctx context.Context, id string) (Account, error) {
+	var acc account
+	err := s.db.QueryRowContext(ctx, `SELECT id, name, role, code FROM accounts WHERE id = ?`, id).
+		Scan(&acc.id, &acc.name, &acc.role, &acc.code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &AccountDoesNotExistError{id: id}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+func (s *sqlite) GetAccounts(ctx context.Context, role string) ([]Account, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name, role, code FROM accounts WHERE role = ?`, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var accounts []Account
+	for rows.Next() {
+		var acc account
+		if err := rows.Scan(&acc.id, &acc.name, &acc.role, &acc.code); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, acc)
+	}
+	return accounts, rows.Err()
+}
+
+func (s *sqlite) AccountExists(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM accounts WHERE id = ?)`, id).Scan(&exists)
+	return exists, err
+}
+
+func (s *sqlite) RenameAccount(ctx context.Context, id string, name string) error {
+	res, err := s.db.ExecContext(ctx, `UPDATE accounts SET name = ? WHERE id = ?`, name, id)
+	return expectAccount(id, res, err)
+}
+
+func (s *sqlite) DeleteAccount(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM accounts WHERE id = ?`, id)
+	return expectAccount(id, res, err)
+}
+
+// ChangeCode relies on the unique index on code to detect collisions,
+// an update which is ignored having hit it.
+func (s *sqlite) ChangeCode(ctx context.Context, id string, code string) (Account, error) {
+	res, err := s.db.ExecContext(ctx, `UPDATE OR IGNORE accounts SET code = ? WHERE id = ?`, code, id)
+	if err != nil {
+		return nil, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		exists, err := s.AccountExists(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, &AccountDoesNotExistError{id: id}
+		}
+		return nil, CodeConflictError{code: code}
+	}
+	return s.GetAccount(ctx, id)
+}
+
+func (s *sqlite) CountAccounts(ctx context.Context) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM accounts`).Scan(&n)
+	return n, err
+}
+
+func (s *sqlite) SetPasswordHash(ctx context.Context, id string, hash []byte) error {
+	var value interface{}
+	if hash != nil {
+		value = hash
+	}
+	res, err := s.db.ExecContext(ctx, `UPDATE accounts SET password_hash = ? WHERE id = ?`, value, id)
+	return expectAccount(id, res, err)
+}
+
+func (s *sqlite) PasswordHash(ctx context.Context, id string) ([]byte, error) {
+	var hash []byte
+	err := s.db.QueryRowContext(ctx, `SELECT password_hash FROM accounts WHERE id = ?`, id).Scan(&hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &AccountDoesNotExistError{id: id}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return hash, nil
+}
+
+// expectAccount turns a statement which changed no rows into an
+// *AccountDoesNotExistError.
+func expectAccount(id string, res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &AccountDoesNotExistError{id: id}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package account
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteStoreKeepsCodesAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "medlock.db")
+
+	db, err := database.Open(path)
+	require.NoError(t, err)
+	store, err := NewSQLiteStore(db)
+	require.NoError(t, err)
+	teacher := NewTeacher("Teacher A")
+	require.NoError(t, store.SaveAccount(context.Background(), teacher))
+	require.NoError(t, db.Close())
+
+	db, err = database.Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+	store, err = NewSQLiteStore(db)
+	require.NoError(t, err)
+
+	loggedIn, err := store.Login(context.Background(), teacher.Code())
+	require.NoError(t, err)
+	assert.Equal(t, teacher, loggedIn)
+	assert.True(t, accountExists(t, store, teacher.ID()))
+}
+
+func TestSQLiteStoreIndexes(t *testing.T) {
+	db, err := database.Open(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = NewSQLiteStore(db)
+	require.NoError(t, err)
+
+	// Login and AccountExists must be index lookups, not table scans.
+	for query, arg := range map[string]string{
+		`SELECT id, name, role, code FROM accounts WHERE code = ?`: "ABCD",
+		`SELECT 1 FROM accounts WHERE id = ?`:                      "id",
+	} {
+		var plan string
+		var id, parent, notUsed int
+		err := db.QueryRow(`EXPLAIN QUERY PLAN `+query, arg).Scan(&id, &parent, &notUsed, &plan)
+		require.NoError(t, err)
+		assert.Contains(t, plan, "USING", query)
+	}
+}
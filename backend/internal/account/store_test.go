@@ -0,0 +1,39 @@
+package account
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/database"
+	"github.com/stretchr/testify/require"
+)
+
+// forEachBackend runs test against a fresh instance of every Store
+// implementation, for tests of code built on top of a Store. The Store
+// contract itself is checked by storetest.
+func forEachBackend(t *testing.T, test func(t *testing.T, store Store)) {
+	t.Run("inmemory", func(t *testing.T) {
+		test(t, NewInMemoryStore())
+	})
+	t.Run("sqlite", func(t *testing.T) {
+		test(t, newTestSQLiteStore(t))
+	})
+}
+
+func newTestSQLiteStore(t *testing.T) Store {
+	db, err := database.Open(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	store, err := NewSQLiteStore(db)
+	require.NoError(t, err)
+	return store
+}
+
+// accountExists reports whether store has an account with id, failing
+// the test if it cannot tell.
+func accountExists(t *testing.T, store Store, id string) bool {
+	t.Helper()
+	exists, err := store.AccountExists(context.Background(), id)
+	require.NoError(t, err)
+	return exists
+}
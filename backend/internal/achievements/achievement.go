@@ -1,11 +1,18 @@
 package achievements
 
+import "time"
+
 type Progress string
 
 var NotStarted Progress = ""
 var Started Progress = "STARTED"
 var Finished Progress = "FINISHED"
 
+// Submitted is progress a student has marked as done which is waiting
+// for a teacher to approve, moving it to Finished, or reject, moving it
+// back to Started.
+var Submitted Progress = "SUBMITTED"
+
 // StudentAchievement represents a particular student's progress of a
 // set Achievement.
 type StudentAchievement struct {
@@ -19,4 +26,23 @@ type StudentAchievement struct {
 type Achievement struct {
 	ID   string
 	Name string
+	// Category groups related achievements, such as the subject they
+	// are part of. It is empty for achievements in none.
+	Category string
+	// DueDate is when students should have finished the achievement,
+	// nil when there is no deadline.
+	DueDate *time.Time
+	// Points are credited to a student when they finish the achievement.
+	Points int
+	// Archived achievements can no longer be worked on, but are kept so
+	// students' history of them stays intact.
+	Archived bool
+	// CreatedAt is zero for achievements created before it was recorded.
+	CreatedAt time.Time
+}
+
+// Overdue reports whether progress on the achievement is unfinished
+// after its due date has passed.
+func (a Achievement) Overdue(progress Progress, now time.Time) bool {
+	return a.DueDate != nil && progress != Finished && now.After(*a.DueDate)
 }
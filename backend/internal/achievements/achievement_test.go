@@ -0,0 +1,33 @@
+package achievements
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverdue(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2022, time.March, 4, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		due      *time.Time
+		progress Progress
+		overdue  bool
+	}{
+		{"no due date", nil, Started, false},
+		{"due in the future", &future, Started, false},
+		{"past due and not started", &past, NotStarted, true},
+		{"past due and started", &past, Started, true},
+		{"past due and awaiting review", &past, Submitted, true},
+		{"past due but finished", &past, Finished, false},
+	}
+	for _, tt := range tests {
+		a := Achievement{ID: "id", Name: "name", DueDate: tt.due}
+		assert.Equal(t, tt.overdue, a.Overdue(tt.progress, now), tt.name)
+	}
+}
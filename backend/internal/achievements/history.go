@@ -0,0 +1,40 @@
+package achievements
+
+import "time"
+
+// ProgressEvent records one change to a student's progress on an
+// achievement and who made it. The events for a student form an
+// append-only log from which their current progress can be rebuilt
+// with Replay.
+type ProgressEvent struct {
+	StudentID     string    `json:"studentId"`
+	AchievementID string    `json:"achievement"`
+	From          Progress  `json:"from"`
+	To            Progress  `json:"to"`
+	ActorID       string    `json:"actorId"`
+	Time          time.Time `json:"time"`
+	// Comment is left by a teacher reviewing a submission.
+	Comment string `json:"comment,omitempty"`
+}
+
+// Replay rebuilds the current progress described by events, which must
+// be in the order they happened.
+func Replay(events []ProgressEvent) []StudentAchievement {
+	var current []StudentAchievement
+	index := make(map[string]int)
+	for _, e := range events {
+		key := e.StudentID + "#" + e.AchievementID
+		sa := StudentAchievement{
+			AchievementID: e.AchievementID,
+			StudentID:     e.StudentID,
+			Progress:      e.To,
+		}
+		if i, ok := index[key]; ok {
+			current[i] = sa
+			continue
+		}
+		index[key] = len(current)
+		current = append(current, sa)
+	}
+	return current
+}
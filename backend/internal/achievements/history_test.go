@@ -0,0 +1,30 @@
+package achievements
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should be empty without events", func(t *testing.T) {
+		assert.Empty(t, Replay(nil))
+	})
+
+	t.Run("should keep the latest progress of every student achievement", func(t *testing.T) {
+		events := []ProgressEvent{
+			{StudentID: "a", AchievementID: "x", From: NotStarted, To: NotStarted},
+			{StudentID: "a", AchievementID: "x", From: NotStarted, To: Started},
+			{StudentID: "b", AchievementID: "x", From: NotStarted, To: Finished},
+			{StudentID: "a", AchievementID: "y", From: NotStarted, To: Started},
+			{StudentID: "a", AchievementID: "x", From: Started, To: Finished},
+		}
+		assert.Equal(t, []StudentAchievement{
+			{StudentID: "a", AchievementID: "x", Progress: Finished},
+			{StudentID: "b", AchievementID: "x", Progress: Finished},
+			{StudentID: "a", AchievementID: "y", Progress: Started},
+		}, Replay(events))
+	})
+}
@@ -0,0 +1,178 @@
+package achievements
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Sort is an order achievements can be listed in.
+type Sort string
+
+// Ties in every order are broken by creation time, then name, then ID,
+// so no two achievements ever compare equal and a listing can carry on
+// from any achievement in it.
+const (
+	SortCreated Sort = "created"
+	SortName    Sort = "name"
+	// SortDueDate puts achievements without a due date last.
+	SortDueDate Sort = "dueDate"
+)
+
+// ErrUnknownCursor is returned when a listing is asked to carry on after
+// an achievement it could never include, whatever its filter: one that
+// does not exist and, for a student's listing, that they have no
+// progress on either.
+var ErrUnknownCursor = errors.New("cursor does not match a listed achievement")
+
+// ListOptions choose which achievements a listing includes, their order,
+// and which page of them to return.
+type ListOptions struct {
+	Filter Filter
+	// Sort defaults to SortCreated.
+	Sort       Sort
+	Descending bool
+	// After is the ID of the last achievement of the previous page, or
+	// empty for the first page. It need not match Filter, so that a next
+	// page can still be listed after the achievement is archived or
+	// changed: the listing carries on from wherever it sorts.
+	After string
+	// Limit is the most achievements to return, or 0 for all of them.
+	Limit int
+}
+
+// Status is whether a listing includes archived achievements.
+type Status string
+
+const (
+	StatusAny      Status = ""
+	StatusActive   Status = "active"
+	StatusArchived Status = "archived"
+)
+
+// Filter narrows a listing down to the achievements matching every one
+// of its fields that is set.
+type Filter struct {
+	// Name matches names containing it, ignoring the case of ASCII
+	// letters only, so that backends without Unicode case folding can
+	// match it the same.
+	Name     string
+	Category string
+	Status   Status
+	// DueFrom and DueBefore match achievements due from DueFrom up to,
+	// but not including, DueBefore. Achievements without a due date
+	// match neither.
+	DueFrom   *time.Time
+	DueBefore *time.Time
+	// Progress only applies to listings of a student's progress, which
+	// it matches if it is any of them. Asking for NotStarted also lists
+	// the active achievements the student has no progress on yet.
+	Progress []Progress
+}
+
+// Matches reports whether a is included by f.
+func (f Filter) Matches(a Achievement) bool {
+	switch {
+	case f.Name != "" && !strings.Contains(lowerASCII(a.Name), lowerASCII(f.Name)):
+		return false
+	case f.Category != "" && a.Category != f.Category:
+		return false
+	case f.Status == StatusActive && a.Archived, f.Status == StatusArchived && !a.Archived:
+		return false
+	case (f.DueFrom != nil || f.DueBefore != nil) && a.DueDate == nil:
+		return false
+	case f.DueFrom != nil && a.DueDate.Before(*f.DueFrom):
+		return false
+	case f.DueBefore != nil && !a.DueDate.Before(*f.DueBefore):
+		return false
+	}
+	return true
+}
+
+// MatchesProgress reports whether a student's progress is included by f.
+func (f Filter) MatchesProgress(progress Progress) bool {
+	if len(f.Progress) == 0 {
+		return true
+	}
+	for _, p := range f.Progress {
+		if p == progress {
+			return true
+		}
+	}
+	return false
+}
+
+// IncludesUnstarted reports whether f lists the active achievements a
+// student has no progress on, as NotStarted.
+func (f Filter) IncludesUnstarted() bool {
+	return len(f.Progress) > 0 && f.MatchesProgress(NotStarted)
+}
+
+func lowerASCII(s string) string {
+	return strings.Map(func(r rune) rune {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, s)
+}
+
+// Less reports whether a is listed before b.
+func (o ListOptions) Less(a, b Achievement) bool {
+	if o.Descending {
+		return o.compare(b, a) < 0
+	}
+	return o.compare(a, b) < 0
+}
+
+func (o ListOptions) compare(a, b Achievement) int {
+	if o.Sort == SortDueDate {
+		if c := compareDueDates(a.DueDate, b.DueDate); c != 0 {
+			return c
+		}
+	}
+	if o.Sort == SortName {
+		if c := compareStrings(a.Name, b.Name); c != 0 {
+			return c
+		}
+	}
+	if c := compareTimes(a.CreatedAt, b.CreatedAt); c != 0 {
+		return c
+	}
+	if c := compareStrings(a.Name, b.Name); c != 0 {
+		return c
+	}
+	return compareStrings(a.ID, b.ID)
+}
+
+func compareDueDates(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return compareTimes(*a, *b)
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+func compareStrings(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
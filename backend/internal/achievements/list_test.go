@@ -0,0 +1,68 @@
+package achievements
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListOptionsLess(t *testing.T) {
+	t.Parallel()
+	early := time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+	older := Achievement{ID: "b", Name: "Zip a Coat", CreatedAt: early, DueDate: &late}
+	newer := Achievement{ID: "a", Name: "Adopt a Pet", CreatedAt: late}
+	sameTime := Achievement{ID: "c", Name: "Zip a Coat", CreatedAt: early, DueDate: &early}
+
+	tests := []struct {
+		name string
+		opts ListOptions
+		a, b Achievement
+		less bool
+	}{
+		{"older first by default", ListOptions{}, older, newer, true},
+		{"newer first descending", ListOptions{Descending: true}, newer, older, true},
+		{"same time by name, then ID", ListOptions{}, older, sameTime, true},
+		{"by name", ListOptions{Sort: SortName}, newer, older, true},
+		{"same name by creation time, then ID", ListOptions{Sort: SortName}, older, sameTime, true},
+		{"earlier due date first", ListOptions{Sort: SortDueDate}, sameTime, older, true},
+		{"no due date last", ListOptions{Sort: SortDueDate}, newer, older, false},
+		{"no due date first descending", ListOptions{Sort: SortDueDate, Descending: true}, newer, older, true},
+		{"not less than itself", ListOptions{}, older, older, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.less, tt.opts.Less(tt.a, tt.b), tt.name)
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	t.Parallel()
+	due := time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)
+	before, after := due.Add(-time.Hour), due.Add(time.Hour)
+	a := Achievement{ID: "id", Name: "Visit the École", Category: "Trips", DueDate: &due}
+
+	tests := []struct {
+		name    string
+		filter  Filter
+		matches bool
+	}{
+		{"everything", Filter{}, true},
+		{"part of the name in another case", Filter{Name: "VISIT THE"}, true},
+		{"only ASCII letters in another case", Filter{Name: "école"}, false},
+		{"another category", Filter{Category: "trips"}, false},
+		{"active", Filter{Status: StatusActive}, true},
+		{"archived", Filter{Status: StatusArchived}, false},
+		{"due from exactly its due date", Filter{DueFrom: &due}, true},
+		{"due before exactly its due date", Filter{DueBefore: &due}, false},
+		{"due around its due date", Filter{DueFrom: &before, DueBefore: &after}, true},
+		{"due after its due date", Filter{DueFrom: &after}, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.matches, tt.filter.Matches(a), tt.name)
+	}
+	assert.False(t, Filter{DueBefore: &after}.Matches(Achievement{}), "without a due date")
+	assert.True(t, Filter{}.MatchesProgress(Started))
+	assert.True(t, Filter{Progress: []Progress{NotStarted, Started}}.MatchesProgress(Started))
+	assert.False(t, Filter{Progress: []Progress{Finished}}.MatchesProgress(NotStarted))
+}
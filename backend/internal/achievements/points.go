@@ -0,0 +1,48 @@
+package achievements
+
+import "time"
+
+var ErrInsufficientPoints error = &kindError{kind: ErrConflict, message: "not enough points"}
+
+// Reasons a PointsEntry was added to a student's ledger.
+const (
+	ReasonAchievementFinished = "ACHIEVEMENT_FINISHED"
+	ReasonAchievementReopened = "ACHIEVEMENT_REOPENED"
+	ReasonRewardRedeemed      = "REWARD_REDEEMED"
+)
+
+// PointsEntry is a single credit (positive Points) or debit (negative
+// Points) in a student's points ledger. A student's balance is the sum
+// of all their entries.
+type PointsEntry struct {
+	StudentID     string    `json:"studentId"`
+	Points        int       `json:"points"`
+	Reason        string    `json:"reason"`
+	AchievementID string    `json:"achievement,omitempty"`
+	RewardID      string    `json:"reward,omitempty"`
+	Time          time.Time `json:"time"`
+}
+
+// Reward is something set by a teacher which students can spend their
+// points on.
+type Reward struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Cost int    `json:"cost"`
+}
+
+// PointsChange returns how many points to add to a student's ledger,
+// and why, when their progress on an achievement worth points moves from
+// previous to next. Finishing credits the achievement's points, while
+// moving back from Finished reverses credited, the points the student
+// currently holds for the achievement. A zero change needs no entry.
+func PointsChange(previous, next Progress, points, credited int) (int, string) {
+	switch {
+	case previous != Finished && next == Finished:
+		return points, ReasonAchievementFinished
+	case previous == Finished && next != Finished:
+		return -credited, ReasonAchievementReopened
+	default:
+		return 0, ""
+	}
+}
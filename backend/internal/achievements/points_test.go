@@ -0,0 +1,30 @@
+package achievements
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointsChange(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		previous Progress
+		next     Progress
+		points   int
+		reason   string
+	}{
+		{"starting", NotStarted, Started, 0, ""},
+		{"finishing", Started, Finished, 10, ReasonAchievementFinished},
+		{"finishing straight away", NotStarted, Finished, 10, ReasonAchievementFinished},
+		{"finishing again", Finished, Finished, 0, ""},
+		{"reopening", Finished, Started, -7, ReasonAchievementReopened},
+		{"resetting", Finished, NotStarted, -7, ReasonAchievementReopened},
+	}
+	for _, tt := range tests {
+		points, reason := PointsChange(tt.previous, tt.next, 10, 7)
+		assert.Equal(t, tt.points, points, tt.name)
+		assert.Equal(t, tt.reason, reason, tt.name)
+	}
+}
@@ -0,0 +1,31 @@
+package achievements
+
+import "time"
+
+var ErrNotSubmitted error = &kindError{kind: ErrConflict, message: "achievement has not been submitted for review"}
+
+// Review is a teacher's decision on an achievement a student has
+// Submitted.
+type Review struct {
+	StudentID     string
+	AchievementID string
+	Approved      bool
+	Comment       string
+	ReviewerID    string
+}
+
+// Outcome is the progress the review moves the student to.
+func (r Review) Outcome() Progress {
+	if r.Approved {
+		return Finished
+	}
+	return Started
+}
+
+// Submission is a student's progress on an achievement that is waiting
+// for review, with when it was submitted.
+type Submission struct {
+	StudentID   string
+	Achievement Achievement
+	SubmittedAt time.Time
+}
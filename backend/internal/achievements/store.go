@@ -1,11 +1,108 @@
 package achievements
 
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound and ErrConflict are the kinds of error a Store returns.
+// Check for them with errors.Is; the more specific errors below match
+// their kind as well as themselves.
+var (
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("conflict")
+)
+
+// Errors a Store returns for achievements, including archived ones
+// where they can no longer be changed, and rewards that do not exist.
+var (
+	ErrAchievementNotFound error = &kindError{kind: ErrNotFound, message: "achievement not found"}
+	ErrRewardNotFound      error = &kindError{kind: ErrNotFound, message: "reward not found"}
+)
+
+// kindError is an error with its own message that also matches kind.
+type kindError struct {
+	kind    error
+	message string
+}
+
+func (e *kindError) Error() string {
+	return e.message
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
+// Store keeps achievements, students' progress on them, and the points
+// and rewards that go with it.
+//
+// Every method takes the context of the request it serves, and gives up
+// with the context's error once it is cancelled, without changing
+// anything.
+//
+// Implementations are safe for concurrent use by multiple goroutines,
+// such as the handlers of simultaneous requests. Each method is atomic:
+// a progression is recorded together with its event and any points it
+// earns, and a reward is only redeemed if the balance covers it at that
+// moment. A sequence of calls is not, so an achievement that exists
+// when checked may have been archived by the next call.
+//
+// GetAllAchievements lists the achievements which are not archived, and
+// GetStudentAchievements all of a student's progress, in the default
+// order of ListOptions. ListAchievements and ListStudentAchievements
+// list those matching a Filter, sorted and paged as asked, with a
+// student's progress filtered and ordered by the achievement it is on.
+//
+// CreateAchievement saves a new achievement with the name, category, due
+// date and points of the one given, returning the ID it is given.
+//
+// GetSubmissions lists the given students' progress which is Submitted,
+// on achievements which exist and are not archived, oldest submission
+// first.
 type Store interface {
-	GetStudentAchievements(id string) ([]StudentAchievement, error)
-	GetStudentsByAchievement(achievement string) []string
-	AddProgression(progression StudentAchievement)
-	AchievementExists(id string) bool
-	CreateAchievement(name string) string
-	GetAllAchievements() []Achievement
-	GetAchievement(id string) (*Achievement, error)
+	GetStudentAchievements(ctx context.Context, id string) ([]StudentAchievement, error)
+	ListStudentAchievements(ctx context.Context, id string, opts ListOptions) ([]StudentAchievement, error)
+	GetAchievementsForStudents(ctx context.Context, ids []string) ([]StudentAchievement, error)
+	GetStudentsByAchievement(ctx context.Context, achievement string) ([]string, error)
+	AddProgression(ctx context.Context, progression StudentAchievement, actorID string) error
+	GetProgressHistory(ctx context.Context, studentID string, achievementID string) ([]ProgressEvent, error)
+	ReviewSubmission(ctx context.Context, review Review) error
+	GetSubmissions(ctx context.Context, studentIDs []string) ([]Submission, error)
+	AchievementExists(ctx context.Context, id string) (bool, error)
+	CreateAchievement(ctx context.Context, achievement Achievement) (string, error)
+	GetAllAchievements(ctx context.Context) ([]Achievement, error)
+	ListAchievements(ctx context.Context, opts ListOptions) ([]Achievement, error)
+	GetAchievement(ctx context.Context, id string) (*Achievement, error)
+	UpdateAchievement(ctx context.Context, achievement Achievement) error
+	ArchiveAchievement(ctx context.Context, id string) error
+	SetDueDate(ctx context.Context, id string, due *time.Time) error
+	GetOverdueAchievements(ctx context.Context, now time.Time) ([]StudentAchievement, error)
+	SetPoints(ctx context.Context, id string, points int) error
+	GetPointsLedger(ctx context.Context, studentID string) ([]PointsEntry, error)
+	GetPointsBalances(ctx context.Context, studentIDs []string) (map[string]int, error)
+	CreateReward(ctx context.Context, name string, cost int) (string, error)
+	GetAllRewards(ctx context.Context) ([]Reward, error)
+	RedeemReward(ctx context.Context, studentID string, rewardID string) error
+}
+
+// Give sets a student up as not having started an achievement, such as
+// one assigned to their class, leaving any progress they have already
+// made alone.
+func Give(ctx context.Context, store Store, studentID string, achievementID string, actorID string) error {
+	progress, err := store.GetStudentAchievements(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	for _, p := range progress {
+		if p.AchievementID == achievementID {
+			return nil
+		}
+	}
+	return store.AddProgression(ctx, StudentAchievement{
+		AchievementID: achievementID,
+		StudentID:     studentID,
+		Progress:      NotStarted,
+	}, actorID)
 }
@@ -0,0 +1,99 @@
+package cards
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/jung-kurt/gofpdf"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Card is printed for each student to show them how to log in.
+type Card struct {
+	Name string
+	Code string
+}
+
+// Cards are laid out on A4 paper in a grid of cardsAcross by cardsDown,
+// each cardWidth by cardHeight millimetres, with dashed borders to cut
+// along.
+const (
+	cardsAcross = 2
+	cardsDown   = 5
+	cardWidth   = 90.0
+	cardHeight  = 54.0
+	padding     = 5.0
+	qrSize      = 32.0
+)
+
+// LoginURL returns the address a card's QR code takes a student to,
+// which is base with the student's login code added to its query.
+func LoginURL(base *url.URL, code string) string {
+	u := *base
+	q := u.Query()
+	q.Set("code", code)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// Render writes a PDF of cut-out login cards headed with title, one per
+// card. When loginURL is not nil each card also has a QR code encoding
+// LoginURL for the student.
+func Render(w io.Writer, title string, cards []Card, loginURL *url.URL) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(title, true)
+	pdf.SetAutoPageBreak(false, 0)
+	// The core fonts only cover Windows-1252, which is enough for most
+	// names in a Western European class.
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pageWidth, pageHeight := pdf.GetPageSize()
+	left := (pageWidth - cardsAcross*cardWidth) / 2
+	top := (pageHeight - cardsDown*cardHeight) / 2
+
+	for i, card := range cards {
+		slot := i % (cardsAcross * cardsDown)
+		if slot == 0 {
+			pdf.AddPage()
+		}
+		x := left + float64(slot%cardsAcross)*cardWidth
+		y := top + float64(slot/cardsAcross)*cardHeight
+
+		pdf.SetDrawColor(160, 160, 160)
+		pdf.SetDashPattern([]float64{2, 2}, 0)
+		pdf.Rect(x, y, cardWidth, cardHeight, "D")
+		pdf.SetDashPattern(nil, 0)
+
+		textWidth := cardWidth - 2*padding
+		if loginURL != nil {
+			png, err := qrcode.Encode(LoginURL(loginURL, card.Code), qrcode.Medium, 256)
+			if err != nil {
+				return err
+			}
+			name := fmt.Sprintf("qr-%d", i)
+			pdf.RegisterImageOptionsReader(name, gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(png))
+			pdf.ImageOptions(name, x+cardWidth-padding-qrSize, y+(cardHeight-qrSize)/2, qrSize, qrSize, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+			textWidth -= qrSize + padding
+		}
+
+		pdf.SetTextColor(100, 100, 100)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.SetXY(x+padding, y+padding)
+		pdf.CellFormat(textWidth, 5, tr(title), "", 2, "L", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.SetXY(x+padding, y+padding+8)
+		pdf.MultiCell(textWidth, 6, tr(card.Name), "", "L", false)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.SetXY(x+padding, y+cardHeight-padding-18)
+		pdf.CellFormat(textWidth, 5, "Login code", "", 2, "L", false, 0, "")
+		pdf.SetFont("Courier", "B", 24)
+		pdf.SetXY(x+padding, y+cardHeight-padding-12)
+		pdf.CellFormat(textWidth, 12, card.Code, "", 2, "L", false, 0, "")
+	}
+	if len(cards) == 0 {
+		pdf.AddPage()
+	}
+	return pdf.Output(w)
+}
@@ -0,0 +1,37 @@
+package cards
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginURL(t *testing.T) {
+	t.Parallel()
+	base, err := url.Parse("https://medlock.example/login?lang=en")
+	require.NoError(t, err)
+	assert.Equal(t, "https://medlock.example/login?code=AB12&lang=en", LoginURL(base, "AB12"))
+	assert.Equal(t, "https://medlock.example/login?lang=en", base.String(), "base should be left alone")
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+	var cards []Card
+	for i := 0; i < 11; i++ {
+		cards = append(cards, Card{Name: fmt.Sprintf("Student %d", i), Code: "AB12"})
+	}
+	loginURL, err := url.Parse("https://medlock.example/login")
+	require.NoError(t, err)
+
+	for _, qr := range []*url.URL{nil, loginURL} {
+		var out bytes.Buffer
+		err := Render(&out, "Class 3B", cards, qr)
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(out.Bytes(), []byte("%PDF-")))
+		assert.Equal(t, 2, bytes.Count(out.Bytes(), []byte("/Type /Page\n")), "ten cards fit on a page")
+	}
+}
@@ -0,0 +1,45 @@
+package classroom
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNotFound = errors.New("classroom not found")
+
+// Classroom groups students under the teacher who runs it. Achievements
+// assigned to a classroom are given to every student in it, including
+// students who join later.
+type Classroom struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	TeacherID      string   `json:"teacherId"`
+	StudentIDs     []string `json:"students"`
+	AchievementIDs []string `json:"achievements"`
+}
+
+func (c Classroom) HasStudent(id string) bool {
+	return contains(c.StudentIDs, id)
+}
+
+// Store keeps classrooms. Implementations are safe to share between
+// goroutines, and the Classrooms they return are copies the caller is
+// free to change. Every method takes the context of the request it
+// serves, and gives up with the context's error once it is cancelled.
+type Store interface {
+	CreateClassroom(ctx context.Context, name string, teacherID string) (string, error)
+	GetClassroom(ctx context.Context, id string) (*Classroom, error)
+	GetTeacherClassrooms(ctx context.Context, teacherID string) ([]Classroom, error)
+	AddStudent(ctx context.Context, classroomID string, studentID string) error
+	RemoveStudent(ctx context.Context, classroomID string, studentID string) error
+	AssignAchievement(ctx context.Context, classroomID string, achievementID string) error
+}
+
+func contains(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package classroom
+
+import (
+	"context"
+	"sync"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+func NewInMemoryStore() Store {
+	return &inmemory{
+		classrooms: make(map[string]Classroom),
+	}
+}
+
+type inmemory struct {
+	mu         sync.RWMutex
+	classrooms map[string]Classroom
+}
+
+func (i *inmemory) CreateClassroom(ctx context.Context, name string, teacherID string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	c := Classroom{
+		ID:        gonanoid.Must(),
+		Name:      name,
+		TeacherID: teacherID,
+	}
+	i.classrooms[c.ID] = c
+	return c.ID, nil
+}
+
+func (i *inmemory) GetClassroom(ctx context.Context, id string) (*Classroom, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	c, ok := i.classrooms[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	c = copyClassroom(c)
+	return &c, nil
+}
+
+func (i *inmemory) GetTeacherClassrooms(ctx context.Context, teacherID string) ([]Classroom, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	var cc []Classroom
+	for _, c := range i.classrooms {
+		if c.TeacherID != teacherID {
+			continue
+		}
+		cc = append(cc, copyClassroom(c))
+	}
+	return cc, nil
+}
+
+func (i *inmemory) AddStudent(ctx context.Context, classroomID string, studentID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	c, ok := i.classrooms[classroomID]
+	if !ok {
+		return ErrNotFound
+	}
+	if c.HasStudent(studentID) {
+		return nil
+	}
+	c.StudentIDs = append(copyIDs(c.StudentIDs), studentID)
+	i.classrooms[classroomID] = c
+	return nil
+}
+
+func (i *inmemory) RemoveStudent(ctx context.Context, classroomID string, studentID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	c, ok := i.classrooms[classroomID]
+	if !ok {
+		return ErrNotFound
+	}
+	var remaining []string
+	for _, id := range c.StudentIDs {
+		if id != studentID {
+			remaining = append(remaining, id)
+		}
+	}
+	c.StudentIDs = remaining
+	i.classrooms[classroomID] = c
+	return nil
+}
+
+func (i *inmemory) AssignAchievement(ctx context.Context, classroomID string, achievementID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	c, ok := i.classrooms[classroomID]
+	if !ok {
+		return ErrNotFound
+	}
+	if contains(c.AchievementIDs, achievementID) {
+		return nil
+	}
+	c.AchievementIDs = append(copyIDs(c.AchievementIDs), achievementID)
+	i.classrooms[classroomID] = c
+	return nil
+}
+
+// copyClassroom stops callers from modifying the stored classroom
+// through the slices they are handed.
+func copyClassroom(c Classroom) Classroom {
+	c.StudentIDs = copyIDs(c.StudentIDs)
+	c.AchievementIDs = copyIDs(c.AchievementIDs)
+	return c
+}
+
+func copyIDs(ids []string) []string {
+	if ids == nil {
+		return nil
+	}
+	return append([]string(nil), ids...)
+}
@@ -0,0 +1,157 @@
+package classroom
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Manchester-Dev/medlock/internal/database"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+var migrations = []string{
+	`CREATE TABLE classrooms (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		teacher_id TEXT NOT NULL
+	);
+	CREATE INDEX classrooms_by_teacher ON classrooms (teacher_id);
+	CREATE TABLE classroom_students (
+		classroom_id TEXT NOT NULL REFERENCES classrooms (id) ON DELETE CASCADE,
+		student_id TEXT NOT NULL,
+		PRIMARY KEY (classroom_id, student_id)
+	);
+	CREATE TABLE classroom_achievements (
+		classroom_id TEXT NOT NULL REFERENCES classrooms (id) ON DELETE CASCADE,
+		achievement_id TEXT NOT NULL,
+		PRIMARY KEY (classroom_id, achievement_id)
+	);`,
+}
+
+// NewSQLiteStore returns a Store persisted in db, migrating the schema
+// to the latest version first.
+func NewSQLiteStore(db *sql.DB) (Store, error) {
+	if err := database.Migrate(db, "classroom", migrations); err != nil {
+		return nil, err
+	}
+	return &sqlite{db: db}, nil
+}
+
+type sqlite struct {
+	db *sql.DB
+}
+
+func (s *sqlite) CreateClassroom(ctx context.Context, name string, teacherID string) (string, error) {
+	id := gonanoid.Must()
+	_, err := s.db.ExecContext(ctx, `INSERT INTO classrooms (id, name, teacher_id) VALUES (?, ?, ?)`, id, name, teacherID)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *sqlite) GetClassroom(ctx context.Context, id string) (*Classroom, error) {
+	c := Classroom{ID: id}
+	err := s.db.QueryRowContext(ctx, `SELECT name, teacher_id FROM classrooms WHERE id = ?`, id).Scan(&c.Name, &c.TeacherID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fill(ctx, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (s *sqlite) GetTeacherClassrooms(ctx context.Context, teacherID string) ([]Classroom, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name FROM classrooms WHERE teacher_id = ? ORDER BY rowid`, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	var cc []Classroom
+	for rows.Next() {
+		c := Classroom{TeacherID: teacherID}
+		if err := rows.Scan(&c.ID, &c.Name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		cc = append(cc, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range cc {
+		if err := s.fill(ctx, &cc[i]); err != nil {
+			return nil, err
+		}
+	}
+	return cc, nil
+}
+
+// fill reads the students and achievements of c, in the order they were
+// added.
+func (s *sqlite) fill(ctx context.Context, c *Classroom) error {
+	var err error
+	c.StudentIDs, err = s.ids(ctx, `SELECT student_id FROM classroom_students WHERE classroom_id = ? ORDER BY rowid`, c.ID)
+	if err != nil {
+		return err
+	}
+	c.AchievementIDs, err = s.ids(ctx, `SELECT achievement_id FROM classroom_achievements WHERE classroom_id = ? ORDER BY rowid`, c.ID)
+	return err
+}
+
+func (s *sqlite) ids(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (s *sqlite) AddStudent(ctx context.Context, classroomID string, studentID string) error {
+	return s.exec(ctx, classroomID, `INSERT INTO classroom_students (classroom_id, student_id) VALUES (?, ?)
+		ON CONFLICT DO NOTHING`, classroomID, studentID)
+}
+
+func (s *sqlite) RemoveStudent(ctx context.Context, classroomID string, studentID string) error {
+	return s.exec(ctx, classroomID, `DELETE FROM classroom_students WHERE classroom_id = ? AND student_id = ?`, classroomID, studentID)
+}
+
+func (s *sqlite) AssignAchievement(ctx context.Context, classroomID string, achievementID string) error {
+	return s.exec(ctx, classroomID, `INSERT INTO classroom_achievements (classroom_id, achievement_id) VALUES (?, ?)
+		ON CONFLICT DO NOTHING`, classroomID, achievementID)
+}
+
+// exec runs query if the classroom with classroomID exists, returning
+// ErrNotFound if not.
+func (s *sqlite) exec(ctx context.Context, classroomID string, query string, args ...interface{}) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var exists bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM classrooms WHERE id = ?)`, classroomID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
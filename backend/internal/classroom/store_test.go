@@ -0,0 +1,176 @@
+package classroom
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// forEachBackend runs test against a fresh instance of every Store
+// implementation.
+func forEachBackend(t *testing.T, test func(t *testing.T, store Store)) {
+	t.Run("inmemory", func(t *testing.T) {
+		t.Parallel()
+		test(t, NewInMemoryStore())
+	})
+	t.Run("sqlite", func(t *testing.T) {
+		t.Parallel()
+		db, err := database.Open(":memory:")
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		store, err := NewSQLiteStore(db)
+		require.NoError(t, err)
+		test(t, store)
+	})
+}
+
+// givenClassroom creates a classroom, failing the test if it cannot.
+func givenClassroom(t *testing.T, store Store, name string, teacherID string) string {
+	t.Helper()
+	id, err := store.CreateClassroom(context.Background(), name, teacherID)
+	require.NoError(t, err)
+	return id
+}
+
+func TestCreateClassroom(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, store Store) {
+		t.Run("should store a classroom for the teacher", func(t *testing.T) {
+			id := givenClassroom(t, store, "Class 3B", "teacher-id")
+			c, err := store.GetClassroom(context.Background(), id)
+			require.NoError(t, err)
+			assert.Equal(t, "Class 3B", c.Name)
+			assert.Equal(t, "teacher-id", c.TeacherID)
+			assert.Empty(t, c.StudentIDs)
+		})
+
+		t.Run("should return not found for unknown classrooms", func(t *testing.T) {
+			_, err := store.GetClassroom(context.Background(), "not-a-class")
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+	})
+}
+
+func TestGetTeacherClassrooms(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, store Store) {
+		id1 := givenClassroom(t, store, "Class 1", "teacher-a")
+		id2 := givenClassroom(t, store, "Class 2", "teacher-a")
+		givenClassroom(t, store, "Class 3", "teacher-b")
+
+		cc, err := store.GetTeacherClassrooms(context.Background(), "teacher-a")
+		require.NoError(t, err)
+		require.Len(t, cc, 2)
+		ids := []string{cc[0].ID, cc[1].ID}
+		assert.ElementsMatch(t, []string{id1, id2}, ids)
+		cc, err = store.GetTeacherClassrooms(context.Background(), "teacher-c")
+		require.NoError(t, err)
+		assert.Empty(t, cc)
+	})
+}
+
+func TestAddAndRemoveStudent(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, store Store) {
+		id := givenClassroom(t, store, "Class 3B", "teacher-id")
+
+		t.Run("should add students once", func(t *testing.T) {
+			require.NoError(t, store.AddStudent(context.Background(), id, "student-a"))
+			require.NoError(t, store.AddStudent(context.Background(), id, "student-b"))
+			require.NoError(t, store.AddStudent(context.Background(), id, "student-a"))
+			c, err := store.GetClassroom(context.Background(), id)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"student-a", "student-b"}, c.StudentIDs)
+			assert.True(t, c.HasStudent("student-b"))
+		})
+
+		t.Run("should remove a student", func(t *testing.T) {
+			require.NoError(t, store.RemoveStudent(context.Background(), id, "student-a"))
+			c, err := store.GetClassroom(context.Background(), id)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"student-b"}, c.StudentIDs)
+		})
+
+		t.Run("should return not found for unknown classrooms", func(t *testing.T) {
+			assert.ErrorIs(t, store.AddStudent(context.Background(), "not-a-class", "student-a"), ErrNotFound)
+			assert.ErrorIs(t, store.RemoveStudent(context.Background(), "not-a-class", "student-a"), ErrNotFound)
+		})
+
+		t.Run("should not let callers change the stored classroom", func(t *testing.T) {
+			c, err := store.GetClassroom(context.Background(), id)
+			require.NoError(t, err)
+			c.StudentIDs[0] = "someone-else"
+			c, err = store.GetClassroom(context.Background(), id)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"student-b"}, c.StudentIDs)
+		})
+	})
+}
+
+func TestAssignAchievement(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, store Store) {
+		id := givenClassroom(t, store, "Class 3B", "teacher-id")
+
+		require.NoError(t, store.AssignAchievement(context.Background(), id, "achievement-a"))
+		require.NoError(t, store.AssignAchievement(context.Background(), id, "achievement-a"))
+		c, err := store.GetClassroom(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"achievement-a"}, c.AchievementIDs)
+		assert.ErrorIs(t, store.AssignAchievement(context.Background(), "not-a-class", "achievement-a"), ErrNotFound)
+	})
+}
+
+func TestCancelledContext(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, store Store) {
+		id := givenClassroom(t, store, "Class 3B", "teacher-id")
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := store.CreateClassroom(ctx, "Class 4A", "teacher-id")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, store.AddStudent(ctx, id, "student-a"), context.Canceled)
+		_, err = store.GetClassroom(ctx, id)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		cc, err := store.GetTeacherClassrooms(context.Background(), "teacher-id")
+		require.NoError(t, err)
+		require.Len(t, cc, 1, "a cancelled call should not change anything")
+		assert.Empty(t, cc[0].StudentIDs, "a cancelled call should not change anything")
+	})
+}
+
+func TestConcurrentClassroomChanges(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, store Store) {
+		id := givenClassroom(t, store, "Class 3B", "teacher-id")
+
+		var wg sync.WaitGroup
+		for n := 0; n < 8; n++ {
+			wg.Add(1)
+			go func(n int) {
+				defer wg.Done()
+				for j := 0; j < 25; j++ {
+					assert.NoError(t, store.AddStudent(context.Background(), id, fmt.Sprintf("student-%d-%d", n, j)))
+					assert.NoError(t, store.AssignAchievement(context.Background(), id, fmt.Sprintf("achievement-%d", j)))
+					_, err := store.GetClassroom(context.Background(), id)
+					assert.NoError(t, err)
+					_, err = store.GetTeacherClassrooms(context.Background(), "teacher-id")
+					assert.NoError(t, err)
+				}
+			}(n)
+		}
+		wg.Wait()
+
+		c, err := store.GetClassroom(context.Background(), id)
+		require.NoError(t, err)
+		assert.Len(t, c.StudentIDs, 8*25)
+		assert.Len(t, c.AchievementIDs, 25)
+	})
+}
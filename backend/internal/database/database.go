@@ -0,0 +1,70 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite"
+)
+
+// Open opens (creating if needed) the SQLite database file at path.
+// Use ":memory:" for a throwaway database.
+func Open(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite only allows a single writer, sharing one connection
+	// avoids "database is locked" errors between handlers and keeps
+	// ":memory:" databases from being split across connections.
+	db.SetMaxOpenConns(1)
+	pragmas := []string{
+		"PRAGMA foreign_keys = ON",
+		"PRAGMA busy_timeout = 5000",
+	}
+	for _, p := range pragmas {
+		if _, err := db.Exec(p); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("database: %s: %w", p, err)
+		}
+	}
+	return db, nil
+}
+
+// Migrate brings the schema owned by component up to date. Each entry
+// in migrations is applied once, in order, and recorded in the
+// schema_migrations table so it is never run again. Migrations must
+// only ever be appended to, never edited or reordered.
+func Migrate(db *sql.DB, component string, migrations []string) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		component TEXT NOT NULL,
+		version INTEGER NOT NULL,
+		PRIMARY KEY (component, version)
+	)`)
+	if err != nil {
+		return err
+	}
+	var current int
+	err = db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations WHERE component = ?`, component).Scan(&current)
+	if err != nil {
+		return err
+	}
+	for v := current + 1; v <= len(migrations); v++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[v-1]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("database: migrating %s to version %d: %w", component, v, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (component, version) VALUES (?, ?)`, component, v); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	db, err := Open(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrations := []string{
+		`CREATE TABLE things (id TEXT PRIMARY KEY)`,
+		`ALTER TABLE things ADD COLUMN name TEXT NOT NULL DEFAULT ''`,
+	}
+
+	t.Run("should apply every migration in order", func(t *testing.T) {
+		require.NoError(t, Migrate(db, "things", migrations[:1]))
+		require.NoError(t, Migrate(db, "things", migrations))
+		_, err := db.Exec(`INSERT INTO things (id, name) VALUES ('a', 'b')`)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should not re-apply migrations that have already run", func(t *testing.T) {
+		assert.NoError(t, Migrate(db, "things", migrations))
+	})
+
+	t.Run("should track versions separately per component", func(t *testing.T) {
+		require.NoError(t, Migrate(db, "others", []string{`CREATE TABLE others (id TEXT)`}))
+		var version int
+		err := db.QueryRow(`SELECT MAX(version) FROM schema_migrations WHERE component = 'things'`).Scan(&version)
+		require.NoError(t, err)
+		assert.Equal(t, 2, version)
+	})
+
+	t.Run("should fail and roll back a broken migration", func(t *testing.T) {
+		err := Migrate(db, "broken", []string{`CREATE TABLE broken (`})
+		require.Error(t, err)
+		var count int
+		err = db.QueryRow(`SELECT COUNT(*) FROM schema_migrations WHERE component = 'broken'`).Scan(&count)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
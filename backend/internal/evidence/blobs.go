@@ -0,0 +1,128 @@
+package evidence
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Blobs stores the contents of evidence files by key. Implementations
+// are safe for concurrent use.
+type Blobs interface {
+	Put(key string, r io.Reader) error
+	// Get returns ErrNotFound for keys which have not been Put.
+	Get(key string) (io.ReadCloser, error)
+	// Delete does nothing for keys which have not been Put.
+	Delete(key string) error
+}
+
+// NewDiskBlobs returns Blobs kept as files in dir, creating it if
+// needed.
+func NewDiskBlobs(dir string) (Blobs, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &disk{dir: dir}, nil
+}
+
+type disk struct {
+	dir string
+}
+
+// path returns where key is stored, refusing keys which could name a
+// file outside of the directory.
+func (d *disk) path(key string) (string, error) {
+	if key == "" || key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+		return "", fmt.Errorf("evidence: invalid blob key %q", key)
+	}
+	return filepath.Join(d.dir, key), nil
+}
+
+// Put writes to a temporary file first so a failed upload never leaves
+// a partial blob behind under key.
+func (d *disk) Put(key string, r io.Reader) error {
+	path, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(d.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (d *disk) Get(key string) (io.ReadCloser, error) {
+	path, err := d.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (d *disk) Delete(key string) error {
+	path, err := d.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// NewInMemoryBlobs returns Blobs which are lost on restart.
+func NewInMemoryBlobs() Blobs {
+	return &inmemoryBlobs{blobs: make(map[string][]byte)}
+}
+
+type inmemoryBlobs struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+func (i *inmemoryBlobs) Put(key string, r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.blobs[key] = b
+	return nil
+}
+
+func (i *inmemoryBlobs) Get(key string) (io.ReadCloser, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	b, ok := i.blobs[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return ioutil.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (i *inmemoryBlobs) Delete(key string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.blobs, key)
+	return nil
+}
@@ -0,0 +1,47 @@
+package evidence
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskBlobs(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	blobs, err := NewDiskBlobs(dir)
+	require.NoError(t, err)
+
+	t.Run("should read back what was put", func(t *testing.T) {
+		require.NoError(t, blobs.Put("key", strings.NewReader("contents")))
+		r, err := blobs.Get("key")
+		require.NoError(t, err)
+		defer r.Close()
+		b, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "contents", string(b))
+	})
+
+	t.Run("should delete blobs", func(t *testing.T) {
+		require.NoError(t, blobs.Put("deleted", strings.NewReader("contents")))
+		require.NoError(t, blobs.Delete("deleted"))
+		_, err := blobs.Get("deleted")
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NoError(t, blobs.Delete("deleted"))
+	})
+
+	t.Run("should refuse keys outside of the directory", func(t *testing.T) {
+		for _, key := range []string{"", "..", "../escape", "a/b"} {
+			assert.Error(t, blobs.Put(key, strings.NewReader("contents")), key)
+		}
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		for _, e := range entries {
+			assert.NotContains(t, e.Name(), ".upload-", "temporary files should be cleaned up")
+		}
+	})
+}
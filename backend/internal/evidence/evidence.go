@@ -0,0 +1,37 @@
+package evidence
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrNotFound = errors.New("evidence not found")
+
+// Evidence is a file, such as a photo, a student has attached to their
+// progress on an achievement as proof of their work. Its contents are
+// kept in Blobs under its ID.
+type Evidence struct {
+	ID            string    `json:"id"`
+	StudentID     string    `json:"studentId"`
+	AchievementID string    `json:"achievement"`
+	FileName      string    `json:"fileName"`
+	ContentType   string    `json:"contentType"`
+	Size          int64     `json:"size"`
+	UploadedBy    string    `json:"uploadedBy"`
+	UploadedAt    time.Time `json:"uploadedAt"`
+}
+
+// Store keeps the records of uploaded Evidence. Lists are in the order
+// the evidence was uploaded. It is safe for concurrent use. Every method
+// takes the context of the request it serves, and gives up with the
+// context's error once it is cancelled.
+type Store interface {
+	SaveEvidence(ctx context.Context, e Evidence) error
+	GetEvidence(ctx context.Context, id string) (*Evidence, error)
+	GetStudentAchievementEvidence(ctx context.Context, studentID string, achievementID string) ([]Evidence, error)
+	DeleteEvidence(ctx context.Context, id string) error
+	// DeleteStudentEvidence removes every record for the student,
+	// returning what was removed so the contents can be deleted too.
+	DeleteStudentEvidence(ctx context.Context, studentID string) ([]Evidence, error)
+}
@@ -0,0 +1,89 @@
+package evidence
+
+import (
+	"context"
+	"sync"
+)
+
+func NewInMemoryStore() Store {
+	return &inmemory{}
+}
+
+type inmemory struct {
+	mu       sync.RWMutex
+	evidence []Evidence
+}
+
+func (i *inmemory) SaveEvidence(ctx context.Context, e Evidence) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.evidence = append(i.evidence, e)
+	return nil
+}
+
+func (i *inmemory) GetEvidence(ctx context.Context, id string) (*Evidence, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for _, e := range i.evidence {
+		if e.ID == id {
+			return &e, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (i *inmemory) GetStudentAchievementEvidence(ctx context.Context, studentID string, achievementID string) ([]Evidence, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	var ee []Evidence
+	for _, e := range i.evidence {
+		if e.StudentID == studentID && e.AchievementID == achievementID {
+			ee = append(ee, e)
+		}
+	}
+	return ee, nil
+}
+
+func (i *inmemory) DeleteEvidence(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	removed := i.deleteWhere(func(e Evidence) bool { return e.ID == id })
+	if len(removed) == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (i *inmemory) DeleteStudentEvidence(ctx context.Context, studentID string) ([]Evidence, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.deleteWhere(func(e Evidence) bool { return e.StudentID == studentID }), nil
+}
+
+func (i *inmemory) deleteWhere(match func(e Evidence) bool) []Evidence {
+	var kept, removed []Evidence
+	for _, e := range i.evidence {
+		if match(e) {
+			removed = append(removed, e)
+			continue
+		}
+		kept = append(kept, e)
+	}
+	i.evidence = kept
+	return removed
+}
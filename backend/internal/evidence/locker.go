@@ -0,0 +1,165 @@
+package evidence
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+var (
+	ErrTooLarge           = errors.New("evidence file is too large")
+	ErrUnsupportedContent = errors.New("evidence file type is not supported")
+)
+
+// Limits restrict what can be uploaded as evidence.
+type Limits struct {
+	// MaxSize is the largest file, in bytes, that can be uploaded.
+	MaxSize int64
+	// ContentTypes are the accepted types, as detected from the file's
+	// contents rather than trusted from the upload.
+	ContentTypes []string
+}
+
+// DefaultLimits accept photos and PDFs of up to 10 MiB.
+var DefaultLimits = Limits{
+	MaxSize: 10 << 20,
+	ContentTypes: []string{
+		"image/jpeg",
+		"image/png",
+		"image/gif",
+		"image/webp",
+		"application/pdf",
+	},
+}
+
+// Locker keeps evidence records in a Store and their contents in Blobs,
+// keeping the two in step.
+type Locker struct {
+	store  Store
+	blobs  Blobs
+	limits Limits
+}
+
+func NewLocker(store Store, blobs Blobs, limits Limits) *Locker {
+	return &Locker{store: store, blobs: blobs, limits: limits}
+}
+
+// MaxSize is the largest file, in bytes, Upload accepts.
+func (l *Locker) MaxSize() int64 {
+	return l.limits.MaxSize
+}
+
+// Upload stores the contents of r as evidence for the student's
+// achievement, returning its record. The ID, content type, size and
+// upload time of e are filled in.
+func (l *Locker) Upload(ctx context.Context, e Evidence, r io.Reader) (Evidence, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return Evidence{}, err
+	}
+	head = head[:n]
+	e.ContentType = http.DetectContentType(head)
+	if !l.allowed(e.ContentType) {
+		return Evidence{}, ErrUnsupportedContent
+	}
+
+	e.ID = gonanoid.Must()
+	// Read one byte past the limit so oversized files can be told apart
+	// from those exactly at it.
+	counted := &countingReader{r: io.MultiReader(bytes.NewReader(head), io.LimitReader(r, l.limits.MaxSize-int64(n)+1))}
+	if err := l.blobs.Put(e.ID, counted); err != nil {
+		return Evidence{}, err
+	}
+	if counted.n > l.limits.MaxSize {
+		l.blobs.Delete(e.ID)
+		return Evidence{}, ErrTooLarge
+	}
+	e.Size = counted.n
+	e.UploadedAt = time.Now()
+	if err := l.store.SaveEvidence(ctx, e); err != nil {
+		l.blobs.Delete(e.ID)
+		return Evidence{}, err
+	}
+	return e, nil
+}
+
+func (l *Locker) allowed(contentType string) bool {
+	for _, t := range l.limits.ContentTypes {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Locker) List(ctx context.Context, studentID string, achievementID string) ([]Evidence, error) {
+	return l.store.GetStudentAchievementEvidence(ctx, studentID, achievementID)
+}
+
+// Open returns the record of the evidence and its contents, which the
+// caller must close.
+func (l *Locker) Open(ctx context.Context, id string) (*Evidence, io.ReadCloser, error) {
+	e, err := l.store.GetEvidence(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	contents, err := l.blobs.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return e, contents, nil
+}
+
+// Remove deletes the evidence with id uploaded for the student's
+// achievement, returning ErrNotFound if it was uploaded for another.
+func (l *Locker) Remove(ctx context.Context, studentID string, achievementID string, id string) error {
+	e, err := l.store.GetEvidence(ctx, id)
+	if err != nil {
+		return err
+	}
+	if e.StudentID != studentID || e.AchievementID != achievementID {
+		return ErrNotFound
+	}
+	if err := l.store.DeleteEvidence(ctx, id); err != nil {
+		return err
+	}
+	return l.blobs.Delete(id)
+}
+
+// RemoveStudent deletes all evidence uploaded for the student.
+func (l *Locker) RemoveStudent(ctx context.Context, studentID string) error {
+	removed, err := l.store.DeleteStudentEvidence(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	return l.deleteBlobs(removed)
+}
+
+// deleteBlobs deletes the contents of every removed record, carrying
+// on past failures so one bad file does not keep the rest around.
+func (l *Locker) deleteBlobs(removed []Evidence) error {
+	var first error
+	for _, e := range removed {
+		if err := l.blobs.Delete(e.ID); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
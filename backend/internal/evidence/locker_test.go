@@ -0,0 +1,116 @@
+package evidence
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// png is the signature content sniffing recognises as a PNG image.
+var png = []byte("\x89PNG\x0D\x0A\x1A\x0A")
+
+func TestLockerUpload(t *testing.T) {
+	t.Parallel()
+	blobs := NewInMemoryBlobs()
+	locker := NewLocker(NewInMemoryStore(), blobs, Limits{MaxSize: 1024, ContentTypes: []string{"image/png"}})
+
+	t.Run("should store the file and its details", func(t *testing.T) {
+		contents := append(append([]byte{}, png...), bytes.Repeat([]byte{1}, 1000)...)
+		e, err := locker.Upload(context.Background(), Evidence{StudentID: "student", AchievementID: "achievement", FileName: "sculpture.png"}, bytes.NewReader(contents))
+		require.NoError(t, err)
+		assert.NotEmpty(t, e.ID)
+		assert.Equal(t, "image/png", e.ContentType)
+		assert.Equal(t, int64(len(contents)), e.Size)
+		assert.False(t, e.UploadedAt.IsZero())
+
+		got, r, err := locker.Open(context.Background(), e.ID)
+		require.NoError(t, err)
+		defer r.Close()
+		assert.Equal(t, "sculpture.png", got.FileName)
+		b, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, contents, b)
+	})
+
+	t.Run("should refuse files over the size limit", func(t *testing.T) {
+		contents := append(append([]byte{}, png...), bytes.Repeat([]byte{1}, 1024)...)
+		_, err := locker.Upload(context.Background(), Evidence{StudentID: "student", AchievementID: "too-large"}, bytes.NewReader(contents))
+		assert.ErrorIs(t, err, ErrTooLarge)
+		ee, err := locker.List(context.Background(), "student", "too-large")
+		require.NoError(t, err)
+		assert.Empty(t, ee)
+	})
+
+	t.Run("should refuse file types which are not allowed", func(t *testing.T) {
+		_, err := locker.Upload(context.Background(), Evidence{StudentID: "student", AchievementID: "achievement"}, strings.NewReader("<html><script>"))
+		assert.ErrorIs(t, err, ErrUnsupportedContent)
+	})
+}
+
+func TestLockerRemove(t *testing.T) {
+	t.Parallel()
+	blobs := NewInMemoryBlobs()
+	locker := NewLocker(NewInMemoryStore(), blobs, DefaultLimits)
+	upload := func(studentID, achievementID string) Evidence {
+		e, err := locker.Upload(context.Background(), Evidence{StudentID: studentID, AchievementID: achievementID}, bytes.NewReader(png))
+		require.NoError(t, err)
+		return e
+	}
+	alices := upload("alice", "plant")
+	other := upload("bob", "compost")
+
+	require.NoError(t, locker.RemoveStudent(context.Background(), "alice"))
+	_, err := blobs.Get(alices.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, _, err = locker.Open(context.Background(), alices.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, r, err := locker.Open(context.Background(), other.ID)
+	require.NoError(t, err)
+	r.Close()
+
+	assert.ErrorIs(t, locker.Remove(context.Background(), "bob", "plant", other.ID), ErrNotFound)
+	require.NoError(t, locker.Remove(context.Background(), "bob", "compost", other.ID))
+	_, err = blobs.Get(other.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, _, err = locker.Open(context.Background(), other.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLockerConcurrentUse(t *testing.T) {
+	t.Parallel()
+	locker := NewLocker(NewInMemoryStore(), NewInMemoryBlobs(), DefaultLimits)
+
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func(studentID string) {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				e, err := locker.Upload(context.Background(), Evidence{StudentID: studentID, AchievementID: "achievement"}, bytes.NewReader(png))
+				if !assert.NoError(t, err) {
+					return
+				}
+				_, r, err := locker.Open(context.Background(), e.ID)
+				if assert.NoError(t, err) {
+					r.Close()
+				}
+				_, err = locker.List(context.Background(), studentID, "achievement")
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, locker.RemoveStudent(context.Background(), studentID))
+		}(fmt.Sprintf("student-%d", n))
+	}
+	wg.Wait()
+
+	ee, err := locker.List(context.Background(), "student-0", "achievement")
+	require.NoError(t, err)
+	assert.Empty(t, ee)
+}
@@ -0,0 +1,110 @@
+package evidence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Manchester-Dev/medlock/internal/database"
+)
+
+var migrations = []string{
+	`CREATE TABLE evidence (
+		id TEXT PRIMARY KEY,
+		student_id TEXT NOT NULL,
+		achievement_id TEXT NOT NULL,
+		file_name TEXT NOT NULL,
+		content_type TEXT NOT NULL,
+		size INTEGER NOT NULL,
+		uploaded_by TEXT NOT NULL,
+		uploaded_at INTEGER NOT NULL
+	);
+	CREATE INDEX evidence_by_student ON evidence (student_id, achievement_id);
+	CREATE INDEX evidence_by_achievement ON evidence (achievement_id);`,
+}
+
+// NewSQLiteStore returns a Store persisted in db, migrating the schema
+// to the latest version first.
+func NewSQLiteStore(db *sql.DB) (Store, error) {
+	if err := database.Migrate(db, "evidence", migrations); err != nil {
+		return nil, err
+	}
+	return &sqlite{db: db}, nil
+}
+
+type sqlite struct {
+	db *sql.DB
+}
+
+const evidenceColumns = `id, student_id, achievement_id, file_name, content_type, size, uploaded_by, uploaded_at`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanEvidence(row scanner) (Evidence, error) {
+	var e Evidence
+	var uploaded int64
+	err := row.Scan(&e.ID, &e.StudentID, &e.AchievementID, &e.FileName, &e.ContentType, &e.Size, &e.UploadedBy, &uploaded)
+	e.UploadedAt = time.Unix(0, uploaded).UTC()
+	return e, err
+}
+
+func (s *sqlite) SaveEvidence(ctx context.Context, e Evidence) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO evidence (`+evidenceColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.ID, e.StudentID, e.AchievementID, e.FileName, e.ContentType, e.Size, e.UploadedBy, e.UploadedAt.UnixNano())
+	return err
+}
+
+func (s *sqlite) GetEvidence(ctx context.Context, id string) (*Evidence, error) {
+	e, err := scanEvidence(s.db.QueryRowContext(ctx, `SELECT `+evidenceColumns+` FROM evidence WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (s *sqlite) GetStudentAchievementEvidence(ctx context.Context, studentID string, achievementID string) ([]Evidence, error) {
+	return s.query(ctx, `SELECT `+evidenceColumns+` FROM evidence WHERE student_id = ? AND achievement_id = ?
+		ORDER BY uploaded_at, rowid`, studentID, achievementID)
+}
+
+func (s *sqlite) DeleteEvidence(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM evidence WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *sqlite) DeleteStudentEvidence(ctx context.Context, studentID string) ([]Evidence, error) {
+	return s.query(ctx, `DELETE FROM evidence WHERE student_id = ? RETURNING `+evidenceColumns, studentID)
+}
+
+func (s *sqlite) query(ctx context.Context, query string, args ...interface{}) ([]Evidence, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ee []Evidence
+	for rows.Next() {
+		e, err := scanEvidence(rows)
+		if err != nil {
+			return nil, err
+		}
+		ee = append(ee, e)
+	}
+	return ee, rows.Err()
+}
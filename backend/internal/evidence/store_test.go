@@ -0,0 +1,124 @@
+package evidence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Manchester-Dev/medlock/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// forEachBackend runs test against a fresh instance of every Store
+// implementation, so they all share one set of behaviour tests.
+func forEachBackend(t *testing.T, test func(t *testing.T, store Store)) {
+	t.Run("inmemory", func(t *testing.T) {
+		test(t, NewInMemoryStore())
+	})
+	t.Run("sqlite", func(t *testing.T) {
+		db, err := database.Open(":memory:")
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		store, err := NewSQLiteStore(db)
+		require.NoError(t, err)
+		test(t, store)
+	})
+}
+
+func givenEvidence(t *testing.T, store Store, id, studentID, achievementID string) Evidence {
+	e := Evidence{
+		ID:            id,
+		StudentID:     studentID,
+		AchievementID: achievementID,
+		FileName:      id + ".jpg",
+		ContentType:   "image/jpeg",
+		Size:          100,
+		UploadedBy:    studentID,
+		UploadedAt:    time.Date(2022, time.March, 4, 12, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, store.SaveEvidence(context.Background(), e))
+	return e
+}
+
+func TestGetEvidence(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, store Store) {
+		e := givenEvidence(t, store, "e1", "student", "achievement")
+
+		got, err := store.GetEvidence(context.Background(), "e1")
+		require.NoError(t, err)
+		assert.Equal(t, e, *got)
+
+		_, err = store.GetEvidence(context.Background(), "not-evidence")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestGetStudentAchievementEvidence(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, store Store) {
+		first := givenEvidence(t, store, "e1", "student", "achievement")
+		second := givenEvidence(t, store, "e2", "student", "achievement")
+		givenEvidence(t, store, "e3", "student", "other")
+		givenEvidence(t, store, "e4", "other", "achievement")
+
+		ee, err := store.GetStudentAchievementEvidence(context.Background(), "student", "achievement")
+		require.NoError(t, err)
+		assert.Equal(t, []Evidence{first, second}, ee)
+	})
+}
+
+func TestDeleteEvidence(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, store Store) {
+		givenEvidence(t, store, "e1", "student", "achievement")
+		require.NoError(t, store.DeleteEvidence(context.Background(), "e1"))
+		_, err := store.GetEvidence(context.Background(), "e1")
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.ErrorIs(t, store.DeleteEvidence(context.Background(), "e1"), ErrNotFound)
+	})
+}
+
+func TestDeleteStudentEvidence(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, store Store) {
+		a := givenEvidence(t, store, "e1", "alice", "plant")
+		b := givenEvidence(t, store, "e2", "alice", "compost")
+		c := givenEvidence(t, store, "e3", "bob", "plant")
+		d := givenEvidence(t, store, "e4", "bob", "compost")
+
+		removed, err := store.DeleteStudentEvidence(context.Background(), "alice")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []Evidence{a, b}, removed)
+
+		left, err := store.GetStudentAchievementEvidence(context.Background(), "bob", "plant")
+		require.NoError(t, err)
+		assert.Equal(t, []Evidence{c}, left)
+		left, err = store.GetStudentAchievementEvidence(context.Background(), "bob", "compost")
+		require.NoError(t, err)
+		assert.Equal(t, []Evidence{d}, left)
+		_, err = store.GetEvidence(context.Background(), "e1")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestCancelledContext(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, store Store) {
+		givenEvidence(t, store, "e1", "student", "achievement")
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.ErrorIs(t, store.SaveEvidence(ctx, Evidence{ID: "e2", StudentID: "student", AchievementID: "achievement"}), context.Canceled)
+		_, err := store.GetEvidence(ctx, "e1")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, store.DeleteEvidence(ctx, "e1"), context.Canceled)
+		_, err = store.DeleteStudentEvidence(ctx, "student")
+		assert.ErrorIs(t, err, context.Canceled)
+
+		ee, err := store.GetStudentAchievementEvidence(context.Background(), "student", "achievement")
+		require.NoError(t, err)
+		assert.Len(t, ee, 1, "a cancelled call should not change anything")
+	})
+}
@@ -0,0 +1,229 @@
+package roster
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+)
+
+const (
+	// MaxStudents is the most students one import may create.
+	MaxStudents = 1000
+	// MaxNameLength is the longest student or class name accepted.
+	MaxNameLength = 100
+)
+
+// Student is one row of an import.
+type Student struct {
+	Name  string
+	Class string
+	// Row is the line of the CSV the student was read from.
+	Row int
+}
+
+// RowError explains why a row of an import was rejected. Row 0 is used
+// for problems with the file as a whole.
+type RowError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// ValidationError rejects a whole import, listing every problem found.
+type ValidationError struct {
+	Rows []RowError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Rows))
+	for i, r := range e.Rows {
+		msgs[i] = fmt.Sprintf("row %d: %s", r.Row, r.Message)
+	}
+	return "invalid import: " + strings.Join(msgs, "; ")
+}
+
+// Parse reads students from a CSV with a name column and an optional
+// class column. A first row of "name" or "name,class" is taken as a
+// header and skipped. Any invalid row fails the whole import with a
+// *ValidationError.
+func Parse(r io.Reader) ([]Student, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	var students []Student
+	var problems []RowError
+	for row := 1; ; row++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			problems = append(problems, RowError{Row: row, Message: "not valid CSV"})
+			break
+		}
+		if row == 1 && isHeader(record) {
+			continue
+		}
+		s, msg := parseRow(record)
+		if msg != "" {
+			problems = append(problems, RowError{Row: row, Message: msg})
+			continue
+		}
+		s.Row = row
+		students = append(students, s)
+	}
+	if len(problems) == 0 && len(students) == 0 {
+		problems = append(problems, RowError{Message: "no students to import"})
+	}
+	if len(students) > MaxStudents {
+		problems = append(problems, RowError{Message: fmt.Sprintf("more than %d students", MaxStudents)})
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Rows: problems}
+	}
+	return students, nil
+}
+
+func isHeader(record []string) bool {
+	return strings.EqualFold(strings.TrimSpace(record[0]), "name")
+}
+
+// parseRow returns the student in record, or why it is invalid.
+func parseRow(record []string) (Student, string) {
+	if len(record) > 2 {
+		return Student{}, "expected a name and an optional class"
+	}
+	s := Student{Name: strings.TrimSpace(record[0])}
+	if len(record) == 2 {
+		s.Class = strings.TrimSpace(record[1])
+	}
+	switch {
+	case s.Name == "":
+		return Student{}, "name is empty"
+	case len(s.Name) > MaxNameLength:
+		return Student{}, fmt.Sprintf("name is longer than %d characters", MaxNameLength)
+	case len(s.Class) > MaxNameLength:
+		return Student{}, fmt.Sprintf("class is longer than %d characters", MaxNameLength)
+	}
+	return s, ""
+}
+
+// Import creates an account for every student, with codes from codes,
+// in a single call to store.SaveAccounts, so either all of them are
+// created or none are. The accounts are returned in the same order as
+// students.
+func Import(ctx context.Context, codes *account.CodeGenerator, store account.Store, students []Student) ([]account.Account, error) {
+	names := make([]string, len(students))
+	for i, s := range students {
+		names[i] = s.Name
+	}
+	return codes.CreateAccounts(ctx, store, names, account.RoleStudent)
+}
+
+// JoinClasses adds each student with a class to the class of that name
+// run by the teacher with teacherID, creating it if the teacher has none
+// by that name, and gives them the achievements assigned to it. The
+// accounts are the students' in the same order, as returned by Import.
+func JoinClasses(ctx context.Context, classroomStore classroom.Store, achievementStore achievements.Store, teacherID string, students []Student, accounts []account.Account) error {
+	existing, err := classroomStore.GetTeacherClassrooms(ctx, teacherID)
+	if err != nil {
+		return err
+	}
+	classes := make(map[string]*classroom.Classroom)
+	for _, c := range existing {
+		c := c
+		classes[c.Name] = &c
+	}
+	for i, s := range students {
+		if s.Class == "" {
+			continue
+		}
+		c, ok := classes[s.Class]
+		if !ok {
+			id, err := classroomStore.CreateClassroom(ctx, s.Class, teacherID)
+			if err != nil {
+				return err
+			}
+			c = &classroom.Classroom{ID: id, Name: s.Class}
+			classes[s.Class] = c
+		}
+		if err := classroomStore.AddStudent(ctx, c.ID, accounts[i].ID()); err != nil {
+			return err
+		}
+		for _, achievementID := range c.AchievementIDs {
+			err := achievements.Give(ctx, achievementStore, accounts[i].ID(), achievementID, teacherID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Enrol creates the students' accounts as Import does and, when
+// teacherID is given, adds them to the teacher's classes as JoinClasses
+// does. The two steps use different stores, so they cannot share a
+// transaction; if joining fails, the accounts are taken out of any class
+// they joined and deleted, leaving the import safe to retry.
+func Enrol(ctx context.Context, codes *account.CodeGenerator, accountStore account.Store, classroomStore classroom.Store, achievementStore achievements.Store, teacherID string, students []Student) ([]account.Account, error) {
+	accounts, err := Import(ctx, codes, accountStore, students)
+	if err != nil {
+		return nil, err
+	}
+	if teacherID == "" {
+		return accounts, nil
+	}
+	err = JoinClasses(ctx, classroomStore, achievementStore, teacherID, students, accounts)
+	if err != nil {
+		// The request may have been cancelled, which should not stop the
+		// accounts from being removed.
+		if uerr := unenrol(context.Background(), accountStore, classroomStore, teacherID, accounts); uerr != nil {
+			return nil, fmt.Errorf("%w, and removing the imported accounts failed: %v", err, uerr)
+		}
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// unenrol takes the accounts out of the teacher's classes and deletes
+// them, carrying on past failures so as much as possible is undone.
+func unenrol(ctx context.Context, accountStore account.Store, classroomStore classroom.Store, teacherID string, accounts []account.Account) error {
+	classes, first := classroomStore.GetTeacherClassrooms(ctx, teacherID)
+	for _, c := range classes {
+		for _, acc := range accounts {
+			if !c.HasStudent(acc.ID()) {
+				continue
+			}
+			if err := classroomStore.RemoveStudent(ctx, c.ID, acc.ID()); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	for _, acc := range accounts {
+		if err := accountStore.DeleteAccount(ctx, acc.ID()); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// WriteCodes writes a CSV of each student's name, class and login code
+// for the teacher to print and hand out.
+func WriteCodes(w io.Writer, students []Student, accounts []account.Account) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"name", "class", "code"}); err != nil {
+		return err
+	}
+	for i, acc := range accounts {
+		if err := cw.Write([]string{acc.Name(), students[i].Class, acc.Code()}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
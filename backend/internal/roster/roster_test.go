@@ -0,0 +1,170 @@
+package roster
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should read names and optional classes", func(t *testing.T) {
+		students, err := Parse(strings.NewReader("name,class\nAda Lovelace,Class 3B\n  Alan Turing  \n"))
+		require.NoError(t, err)
+		assert.Equal(t, []Student{
+			{Name: "Ada Lovelace", Class: "Class 3B", Row: 2},
+			{Name: "Alan Turing", Row: 3},
+		}, students)
+	})
+
+	t.Run("should not need a header", func(t *testing.T) {
+		students, err := Parse(strings.NewReader("Ada Lovelace\n"))
+		require.NoError(t, err)
+		assert.Equal(t, []Student{{Name: "Ada Lovelace", Row: 1}}, students)
+	})
+
+	t.Run("should reject the whole import listing every invalid row", func(t *testing.T) {
+		in := "name,class\nAda Lovelace\n,Class 3B\nAlan Turing,Class 3B,extra\n" + strings.Repeat("x", MaxNameLength+1) + "\n"
+		_, err := Parse(strings.NewReader(in))
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Equal(t, []RowError{
+			{Row: 3, Message: "name is empty"},
+			{Row: 4, Message: "expected a name and an optional class"},
+			{Row: 5, Message: "name is longer than 100 characters"},
+		}, verr.Rows)
+	})
+
+	t.Run("should reject an empty import", func(t *testing.T) {
+		_, err := Parse(strings.NewReader("name,class\n"))
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Equal(t, []RowError{{Message: "no students to import"}}, verr.Rows)
+	})
+
+	t.Run("should reject malformed CSV", func(t *testing.T) {
+		_, err := Parse(strings.NewReader("Ada \"Lovelace\n"))
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Equal(t, "not valid CSV", verr.Rows[0].Message)
+	})
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+	store := account.NewInMemoryStore()
+	students := []Student{{Name: "Ada Lovelace", Class: "Class 3B"}, {Name: "Alan Turing"}}
+
+	codes, err := account.NewCodeGenerator(account.DefaultCodeOptions)
+	require.NoError(t, err)
+	accounts, err := Import(context.Background(), codes, store, students)
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+	for i, acc := range accounts {
+		assert.Equal(t, students[i].Name, acc.Name())
+		assert.Equal(t, account.RoleStudent, acc.Role())
+		loggedIn, err := store.Login(context.Background(), acc.Code())
+		require.NoError(t, err)
+		assert.Equal(t, acc.ID(), loggedIn.ID())
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteCodes(&out, students, accounts))
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"name", "class", "code"},
+		{"Ada Lovelace", "Class 3B", accounts[0].Code()},
+		{"Alan Turing", "", accounts[1].Code()},
+	}, records)
+}
+
+func TestJoinClasses(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	accountStore := account.NewInMemoryStore()
+	achievementStore := store.NewInMemory()
+	classroomStore := classroom.NewInMemoryStore()
+	existing, err := classroomStore.CreateClassroom(context.Background(), "Class 3B", "teacher")
+	require.NoError(t, err)
+	assigned, err := achievementStore.CreateAchievement(ctx, achievements.Achievement{Name: "Plant some Seeds"})
+	require.NoError(t, err)
+	require.NoError(t, classroomStore.AssignAchievement(context.Background(), existing, assigned))
+	_, err = classroomStore.CreateClassroom(context.Background(), "Class 4A", "someone else")
+	require.NoError(t, err)
+
+	students := []Student{{Name: "Ada Lovelace", Class: "Class 3B"}, {Name: "Alan Turing", Class: "Class 4A"}, {Name: "Grace Hopper"}}
+	codes, err := account.NewCodeGenerator(account.DefaultCodeOptions)
+	require.NoError(t, err)
+	accounts, err := Import(ctx, codes, accountStore, students)
+	require.NoError(t, err)
+	require.NoError(t, JoinClasses(ctx, classroomStore, achievementStore, "teacher", students, accounts))
+
+	classes, err := classroomStore.GetTeacherClassrooms(context.Background(), "teacher")
+	require.NoError(t, err)
+	require.Len(t, classes, 2, "a class only another teacher has should be created")
+	for _, c := range classes {
+		switch c.Name {
+		case "Class 3B":
+			assert.Equal(t, existing, c.ID)
+			assert.Equal(t, []string{accounts[0].ID()}, c.StudentIDs)
+		case "Class 4A":
+			assert.Equal(t, []string{accounts[1].ID()}, c.StudentIDs)
+		default:
+			t.Errorf("unexpected class %q", c.Name)
+		}
+	}
+	progress, err := achievementStore.GetStudentAchievements(ctx, accounts[0].ID())
+	require.NoError(t, err)
+	require.Len(t, progress, 1)
+	assert.Equal(t, assigned, progress[0].AchievementID)
+	progress, err = achievementStore.GetStudentAchievements(ctx, accounts[2].ID())
+	require.NoError(t, err)
+	assert.Empty(t, progress)
+}
+
+// flakyClassrooms fails every AddStudent after the first.
+type flakyClassrooms struct {
+	classroom.Store
+	added int
+}
+
+func (f *flakyClassrooms) AddStudent(ctx context.Context, classroomID string, studentID string) error {
+	f.added++
+	if f.added > 1 {
+		return errors.New("classroom store is down")
+	}
+	return f.Store.AddStudent(ctx, classroomID, studentID)
+}
+
+func TestEnrolUndoesFailedImport(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	accountStore := account.NewInMemoryStore()
+	classroomStore := &flakyClassrooms{Store: classroom.NewInMemoryStore()}
+	codes, err := account.NewCodeGenerator(account.DefaultCodeOptions)
+	require.NoError(t, err)
+
+	students := []Student{{Name: "Ada Lovelace", Class: "Class 3B"}, {Name: "Alan Turing", Class: "Class 3B"}}
+	_, err = Enrol(ctx, codes, accountStore, classroomStore, store.NewInMemory(), "teacher", students)
+	assert.EqualError(t, err, "classroom store is down")
+
+	left, err := accountStore.GetAccounts(ctx, account.RoleStudent)
+	require.NoError(t, err)
+	assert.Empty(t, left, "no account should be kept from a failed import")
+	classes, err := classroomStore.GetTeacherClassrooms(ctx, "teacher")
+	require.NoError(t, err)
+	require.Len(t, classes, 1)
+	assert.Empty(t, classes[0].StudentIDs, "students who joined should be taken out again")
+}
@@ -0,0 +1,160 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned by Verify for any token that was not
+// issued by this Manager, has been tampered with or has expired.
+var ErrInvalidToken = errors.New("invalid session token")
+
+// Manager issues and verifies session tokens. A token carries the
+// account id and expiry, signed with HMAC-SHA256 using a local secret,
+// so verifying one needs no server side state. It also carries a
+// fingerprint of the credential used to log in, so tokens can be
+// revoked by changing the credential.
+type Manager struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+type claims struct {
+	Subject    string `json:"sub"`
+	Expires    int64  `json:"exp"`
+	Credential string `json:"crd,omitempty"`
+}
+
+var encoding = base64.RawURLEncoding
+
+// SecretSize is the length in bytes of the secrets NewSecret generates,
+// and the shortest one LoadOrCreateSecret accepts.
+const SecretSize = 32
+
+// ErrShortSecret is returned by LoadOrCreateSecret for a stored secret
+// shorter than SecretSize, such as an empty or truncated file, which
+// would make tokens easy to forge.
+var ErrShortSecret = errors.New("session secret is too short")
+
+func NewManager(secret []byte, ttl time.Duration) *Manager {
+	return &Manager{
+		secret: secret,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// Issue returns a signed token for the account with the given id,
+// which logged in with credential.
+func (m *Manager) Issue(accountID string, credential string) (string, error) {
+	payload, err := json.Marshal(claims{
+		Subject:    accountID,
+		Expires:    m.now().Add(m.ttl).Unix(),
+		Credential: m.fingerprint(credential),
+	})
+	if err != nil {
+		return "", err
+	}
+	body := encoding.EncodeToString(payload)
+	return body + "." + encoding.EncodeToString(m.sign(body)), nil
+}
+
+// Verify checks the token's signature and expiry and returns the id of
+// the account it was issued to.
+func (m *Manager) Verify(token string) (string, error) {
+	c, err := m.parse(token)
+	if err != nil {
+		return "", err
+	}
+	return c.Subject, nil
+}
+
+// IssuedFor reports whether a valid token was issued for a login with
+// credential. Once an account's credential changes, tokens issued for
+// the old one should no longer be accepted.
+func (m *Manager) IssuedFor(token string, credential string) bool {
+	c, err := m.parse(token)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(c.Credential), []byte(m.fingerprint(credential)))
+}
+
+func (m *Manager) parse(token string) (claims, error) {
+	var c claims
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return c, ErrInvalidToken
+	}
+	sig, err := encoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sig, m.sign(parts[0])) {
+		return c, ErrInvalidToken
+	}
+	payload, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return c, ErrInvalidToken
+	}
+	if err := json.Unmarshal(payload, &c); err != nil || c.Subject == "" {
+		return c, ErrInvalidToken
+	}
+	if m.now().Unix() >= c.Expires {
+		return c, ErrInvalidToken
+	}
+	return c, nil
+}
+
+// fingerprint identifies credential without revealing it to anyone
+// who reads a token.
+func (m *Manager) fingerprint(credential string) string {
+	return encoding.EncodeToString(m.sign("credential:" + credential)[:12])
+}
+
+func (m *Manager) sign(body string) []byte {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(body))
+	return mac.Sum(nil)
+}
+
+// LoadOrCreateSecret reads the signing secret stored at path, generating
+// and saving a new random one if the file does not exist yet. Keeping
+// the secret on disk means issued tokens stay valid across restarts.
+// A file holding fewer than SecretSize bytes is refused rather than
+// replaced, in case it was truncated by mistake.
+func LoadOrCreateSecret(path string) ([]byte, error) {
+	secret, err := os.ReadFile(path)
+	if err == nil {
+		if len(secret) < SecretSize {
+			return nil, fmt.Errorf("%s: %w", path, ErrShortSecret)
+		}
+		return secret, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	secret, err = NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, secret, 0600); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// NewSecret returns a random secret suitable for signing tokens.
+func NewSecret() ([]byte, error) {
+	secret := make([]byte, SecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
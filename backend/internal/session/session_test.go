@@ -0,0 +1,94 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueAndVerify(t *testing.T) {
+	t.Parallel()
+	m := NewManager([]byte("secret"), time.Hour)
+
+	t.Run("should verify a token it issued", func(t *testing.T) {
+		token, err := m.Issue("account-id", "CODE")
+		require.NoError(t, err)
+		id, err := m.Verify(token)
+		require.NoError(t, err)
+		assert.Equal(t, "account-id", id)
+	})
+
+	t.Run("should reject a token signed with a different secret", func(t *testing.T) {
+		other := NewManager([]byte("other secret"), time.Hour)
+		token, err := other.Issue("account-id", "CODE")
+		require.NoError(t, err)
+		_, err = m.Verify(token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("should reject a token with a tampered payload", func(t *testing.T) {
+		token, err := m.Issue("account-id", "CODE")
+		require.NoError(t, err)
+		forged, err := m.Issue("someone-else", "CODE")
+		require.NoError(t, err)
+		tampered := strings.Split(forged, ".")[0] + "." + strings.Split(token, ".")[1]
+		_, err = m.Verify(tampered)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("should reject malformed tokens", func(t *testing.T) {
+		for _, token := range []string{"", "abc", "a.b.c", "!!.!!"} {
+			_, err := m.Verify(token)
+			assert.ErrorIs(t, err, ErrInvalidToken, token)
+		}
+	})
+
+	t.Run("should reject an expired token", func(t *testing.T) {
+		expiring := NewManager([]byte("secret"), time.Minute)
+		token, err := expiring.Issue("account-id", "CODE")
+		require.NoError(t, err)
+		expiring.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		_, err = expiring.Verify(token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestIssuedFor(t *testing.T) {
+	t.Parallel()
+	m := NewManager([]byte("secret"), time.Hour)
+	token, err := m.Issue("account-id", "CODE")
+	require.NoError(t, err)
+
+	assert.True(t, m.IssuedFor(token, "CODE"))
+	assert.False(t, m.IssuedFor(token, "NEW1"), "tokens should be revoked by changing the credential")
+	assert.False(t, m.IssuedFor("not-a-token", "CODE"))
+	assert.NotContains(t, token, "CODE", "the credential should not be readable from the token")
+}
+
+func TestLoadOrCreateSecret(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "session.key")
+
+	created, err := LoadOrCreateSecret(path)
+	require.NoError(t, err)
+	assert.Len(t, created, 32)
+
+	loaded, err := LoadOrCreateSecret(path)
+	require.NoError(t, err)
+	assert.Equal(t, created, loaded)
+}
+
+func TestLoadOrCreateSecretRefusesShortSecrets(t *testing.T) {
+	t.Parallel()
+	for _, size := range []int{0, 1, SecretSize - 1} {
+		path := filepath.Join(t.TempDir(), "session.key")
+		require.NoError(t, os.WriteFile(path, make([]byte, size), 0600))
+		_, err := LoadOrCreateSecret(path)
+		assert.ErrorIs(t, err, ErrShortSecret, size)
+	}
+}
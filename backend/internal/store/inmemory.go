@@ -1,57 +1,254 @@
 package store
 
 import (
-	"errors"
+	"context"
 	"github.com/Manchester-Dev/medlock/internal/achievements"
 	gonanoid "github.com/matoous/go-nanoid/v2"
+	"sort"
+	"sync"
+	"time"
 )
 
 func NewInMemory() achievements.Store {
 	return &inmemory{
 		achievements:    make(map[string]achievements.StudentAchievement),
 		achievementList: make(map[string]achievements.Achievement),
+		rewards:         make(map[string]achievements.Reward),
 	}
 }
 
+// inmemory guards everything with mu, so a progression, its event and
+// its points are seen together or not at all.
 type inmemory struct {
+	mu              sync.RWMutex
 	achievements    map[string]achievements.StudentAchievement
 	achievementList map[string]achievements.Achievement
+	events          []achievements.ProgressEvent
+	ledger          []achievements.PointsEntry
+	rewards         map[string]achievements.Reward
 }
 
-func (i *inmemory) GetAchievement(id string) (*achievements.Achievement, error) {
+func (i *inmemory) GetAchievement(ctx context.Context, id string) (*achievements.Achievement, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
 	a, ok := i.achievementList[id]
 	if !ok {
-		return nil, errors.New("achievement not found")
+		return nil, achievements.ErrAchievementNotFound
 	}
+	a.DueDate = copyTime(a.DueDate)
 	return &a, nil
 }
 
-func (i *inmemory) GetAllAchievements() []achievements.Achievement {
+func (i *inmemory) GetAllAchievements(ctx context.Context) ([]achievements.Achievement, error) {
+	return i.ListAchievements(ctx, achievements.ListOptions{Filter: achievements.Filter{Status: achievements.StatusActive}})
+}
+
+func (i *inmemory) ListAchievements(ctx context.Context, opts achievements.ListOptions) ([]achievements.Achievement, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	var after *achievements.Achievement
+	if opts.After != "" {
+		a, ok := i.achievementList[opts.After]
+		if !ok {
+			return nil, achievements.ErrUnknownCursor
+		}
+		after = &a
+	}
 	var aa []achievements.Achievement
 	for _, a := range i.achievementList {
+		if !opts.Filter.Matches(a) || (after != nil && !opts.Less(*after, a)) {
+			continue
+		}
+		a.DueDate = copyTime(a.DueDate)
 		aa = append(aa, a)
 	}
-	return aa
+	sort.Slice(aa, func(x, y int) bool {
+		return opts.Less(aa[x], aa[y])
+	})
+	if opts.Limit > 0 && len(aa) > opts.Limit {
+		aa = aa[:opts.Limit]
+	}
+	return aa, nil
+}
+
+func (i *inmemory) UpdateAchievement(ctx context.Context, achievement achievements.Achievement) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	a, ok := i.achievementList[achievement.ID]
+	if !ok || a.Archived {
+		return achievements.ErrAchievementNotFound
+	}
+	a.Name = achievement.Name
+	a.Category = achievement.Category
+	a.DueDate = copyTime(achievement.DueDate)
+	a.Points = achievement.Points
+	i.achievementList[a.ID] = a
+	return nil
 }
 
-func (i *inmemory) CreateAchievement(name string) string {
+func (i *inmemory) ArchiveAchievement(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	a, ok := i.achievementList[id]
+	if !ok || a.Archived {
+		return achievements.ErrAchievementNotFound
+	}
+	a.Archived = true
+	i.achievementList[id] = a
+	return nil
+}
+
+func (i *inmemory) CreateAchievement(ctx context.Context, achievement achievements.Achievement) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
 	ach := achievements.Achievement{
-		ID:   gonanoid.Must(),
-		Name: name,
+		ID:        gonanoid.Must(),
+		Name:      achievement.Name,
+		Category:  achievement.Category,
+		DueDate:   copyTime(achievement.DueDate),
+		Points:    achievement.Points,
+		CreatedAt: time.Now().UTC(),
 	}
 	i.achievementList[ach.ID] = ach
-	return ach.ID
+	return ach.ID, nil
+}
+
+func (i *inmemory) SetDueDate(ctx context.Context, id string, due *time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	a, ok := i.achievementList[id]
+	if !ok || a.Archived {
+		return achievements.ErrAchievementNotFound
+	}
+	a.DueDate = copyTime(due)
+	i.achievementList[id] = a
+	return nil
+}
+
+func (i *inmemory) GetOverdueAchievements(ctx context.Context, now time.Time) ([]achievements.StudentAchievement, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	var aa []achievements.StudentAchievement
+	for _, sa := range i.achievements {
+		a, ok := i.achievementList[sa.AchievementID]
+		if !ok || a.Archived || !a.Overdue(sa.Progress, now) {
+			continue
+		}
+		aa = append(aa, sa)
+	}
+	return aa, nil
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+func (i *inmemory) AchievementExists(ctx context.Context, id string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	a, ok := i.achievementList[id]
+	return ok && !a.Archived, nil
 }
 
-func (i *inmemory) AchievementExists(id string) bool {
-	_, ok := i.achievementList[id]
-	return ok
+func (i *inmemory) GetStudentAchievements(ctx context.Context, studentID string) ([]achievements.StudentAchievement, error) {
+	return i.ListStudentAchievements(ctx, studentID, achievements.ListOptions{})
 }
 
-func (i *inmemory) GetStudentAchievements(studentID string) ([]achievements.StudentAchievement, error) {
+// ListStudentAchievements filters and orders progress by the achievement
+// it is on, treating an achievement missing from i.achievementList as
+// one with only an ID, and one the student has no progress on as
+// NotStarted.
+func (i *inmemory) ListStudentAchievements(ctx context.Context, studentID string, opts achievements.ListOptions) ([]achievements.StudentAchievement, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	details := func(id string) achievements.Achievement {
+		a, ok := i.achievementList[id]
+		if !ok {
+			return achievements.Achievement{ID: id}
+		}
+		return a
+	}
+	var after *achievements.Achievement
+	if opts.After != "" {
+		_, hasProgress := i.achievements[studentID+"#"+opts.After]
+		if _, exists := i.achievementList[opts.After]; !hasProgress && !exists {
+			return nil, achievements.ErrUnknownCursor
+		}
+		a := details(opts.After)
+		after = &a
+	}
+	var aa []achievements.StudentAchievement
+	include := func(sa achievements.StudentAchievement) {
+		a := details(sa.AchievementID)
+		if opts.Filter.Matches(a) && (after == nil || opts.Less(*after, a)) {
+			aa = append(aa, sa)
+		}
+	}
+	for _, sa := range i.achievements {
+		if sa.StudentID == studentID && opts.Filter.MatchesProgress(sa.Progress) {
+			include(sa)
+		}
+	}
+	if opts.Filter.IncludesUnstarted() {
+		for id, a := range i.achievementList {
+			if _, ok := i.achievements[studentID+"#"+id]; !ok && !a.Archived {
+				include(achievements.StudentAchievement{StudentID: studentID, AchievementID: id, Progress: achievements.NotStarted})
+			}
+		}
+	}
+	sort.Slice(aa, func(x, y int) bool {
+		return opts.Less(details(aa[x].AchievementID), details(aa[y].AchievementID))
+	})
+	if opts.Limit > 0 && len(aa) > opts.Limit {
+		aa = aa[:opts.Limit]
+	}
+	return aa, nil
+}
+
+func (i *inmemory) GetAchievementsForStudents(ctx context.Context, studentIDs []string) ([]achievements.StudentAchievement, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	wanted := make(map[string]bool, len(studentIDs))
+	for _, id := range studentIDs {
+		wanted[id] = true
+	}
 	var aa []achievements.StudentAchievement
 	for _, sa := range i.achievements {
-		if sa.StudentID != studentID {
+		if !wanted[sa.StudentID] {
 			continue
 		}
 		aa = append(aa, sa)
@@ -59,7 +256,12 @@ func (i *inmemory) GetStudentAchievements(studentID string) ([]achievements.Stud
 	return aa, nil
 }
 
-func (i *inmemory) GetStudentsByAchievement(id string) []string {
+func (i *inmemory) GetStudentsByAchievement(ctx context.Context, id string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
 	var students []string
 	for _, sa := range i.achievements {
 		if sa.AchievementID != id {
@@ -67,9 +269,225 @@ func (i *inmemory) GetStudentsByAchievement(id string) []string {
 		}
 		students = append(students, sa.StudentID)
 	}
-	return students
+	return students, nil
+}
+
+// AddProgression appends to the progress event log, keeping the latest
+// progress of each student achievement in i.achievements so it does not
+// have to be replayed on every read.
+func (i *inmemory) AddProgression(ctx context.Context, progression achievements.StudentAchievement, actorID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.addProgression(progression, actorID, "")
+	return nil
+}
+
+func (i *inmemory) ReviewSubmission(ctx context.Context, review achievements.Review) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	key := review.StudentID + "#" + review.AchievementID
+	if i.achievements[key].Progress != achievements.Submitted {
+		return achievements.ErrNotSubmitted
+	}
+	i.addProgression(achievements.StudentAchievement{
+		AchievementID: review.AchievementID,
+		StudentID:     review.StudentID,
+		Progress:      review.Outcome(),
+	}, review.ReviewerID, review.Comment)
+	return nil
+}
+
+func (i *inmemory) addProgression(progression achievements.StudentAchievement, actorID string, comment string) {
+	key := progression.StudentID + "#" + progression.AchievementID
+	previous := i.achievements[key].Progress
+	now := time.Now()
+	i.events = append(i.events, achievements.ProgressEvent{
+		StudentID:     progression.StudentID,
+		AchievementID: progression.AchievementID,
+		From:          previous,
+		To:            progression.Progress,
+		ActorID:       actorID,
+		Time:          now,
+		Comment:       comment,
+	})
+	i.achievements[key] = progression
+
+	credited := 0
+	for _, e := range i.ledger {
+		if e.StudentID == progression.StudentID && e.AchievementID == progression.AchievementID {
+			credited += e.Points
+		}
+	}
+	points, reason := achievements.PointsChange(previous, progression.Progress, i.achievementList[progression.AchievementID].Points, credited)
+	if points == 0 {
+		return
+	}
+	i.ledger = append(i.ledger, achievements.PointsEntry{
+		StudentID:     progression.StudentID,
+		Points:        points,
+		Reason:        reason,
+		AchievementID: progression.AchievementID,
+		Time:          now,
+	})
+}
+
+func (i *inmemory) GetSubmissions(ctx context.Context, studentIDs []string) ([]achievements.Submission, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	students := make(map[string]bool, len(studentIDs))
+	for _, id := range studentIDs {
+		students[id] = true
+	}
+	// The latest event of submitted progress is its submission.
+	latest := make(map[string]int)
+	for n, e := range i.events {
+		if students[e.StudentID] {
+			latest[e.StudentID+"#"+e.AchievementID] = n
+		}
+	}
+	var submitted []int
+	for key, sa := range i.achievements {
+		a, ok := i.achievementList[sa.AchievementID]
+		if !students[sa.StudentID] || sa.Progress != achievements.Submitted || !ok || a.Archived {
+			continue
+		}
+		submitted = append(submitted, latest[key])
+	}
+	sort.Ints(submitted)
+	submissions := make([]achievements.Submission, len(submitted))
+	for n, event := range submitted {
+		e := i.events[event]
+		a := i.achievementList[e.AchievementID]
+		a.DueDate = copyTime(a.DueDate)
+		submissions[n] = achievements.Submission{StudentID: e.StudentID, Achievement: a, SubmittedAt: e.Time}
+	}
+	return submissions, nil
+}
+
+func (i *inmemory) GetProgressHistory(ctx context.Context, studentID string, achievementID string) ([]achievements.ProgressEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	var events []achievements.ProgressEvent
+	for _, e := range i.events {
+		if e.StudentID == studentID && e.AchievementID == achievementID {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (i *inmemory) SetPoints(ctx context.Context, id string, points int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	a, ok := i.achievementList[id]
+	if !ok || a.Archived {
+		return achievements.ErrAchievementNotFound
+	}
+	a.Points = points
+	i.achievementList[id] = a
+	return nil
+}
+
+func (i *inmemory) GetPointsLedger(ctx context.Context, studentID string) ([]achievements.PointsEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	var entries []achievements.PointsEntry
+	for _, e := range i.ledger {
+		if e.StudentID == studentID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func (i *inmemory) GetPointsBalances(ctx context.Context, studentIDs []string) (map[string]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.pointsBalances(studentIDs), nil
 }
 
-func (i *inmemory) AddProgression(progression achievements.StudentAchievement) {
-	i.achievements[progression.StudentID+"#"+progression.AchievementID] = progression
+func (i *inmemory) pointsBalances(studentIDs []string) map[string]int {
+	balances := make(map[string]int, len(studentIDs))
+	for _, id := range studentIDs {
+		balances[id] = 0
+	}
+	for _, e := range i.ledger {
+		if _, ok := balances[e.StudentID]; ok {
+			balances[e.StudentID] += e.Points
+		}
+	}
+	return balances
+}
+
+func (i *inmemory) CreateReward(ctx context.Context, name string, cost int) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	r := achievements.Reward{
+		ID:   gonanoid.Must(),
+		Name: name,
+		Cost: cost,
+	}
+	i.rewards[r.ID] = r
+	return r.ID, nil
+}
+
+func (i *inmemory) GetAllRewards(ctx context.Context) ([]achievements.Reward, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	var rr []achievements.Reward
+	for _, r := range i.rewards {
+		rr = append(rr, r)
+	}
+	return rr, nil
+}
+
+func (i *inmemory) RedeemReward(ctx context.Context, studentID string, rewardID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	r, ok := i.rewards[rewardID]
+	if !ok {
+		return achievements.ErrRewardNotFound
+	}
+	balances := i.pointsBalances([]string{studentID})
+	if balances[studentID] < r.Cost {
+		return achievements.ErrInsufficientPoints
+	}
+	i.ledger = append(i.ledger, achievements.PointsEntry{
+		StudentID: studentID,
+		Points:    -r.Cost,
+		Reason:    achievements.ReasonRewardRedeemed,
+		RewardID:  r.ID,
+		Time:      time.Now(),
+	})
+	return nil
 }
@@ -0,0 +1,668 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/database"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+var migrations = []string{
+	`CREATE TABLE achievements (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL
+	);
+	CREATE TABLE student_achievements (
+		student_id TEXT NOT NULL,
+		achievement_id TEXT NOT NULL,
+		progress TEXT NOT NULL,
+		PRIMARY KEY (student_id, achievement_id)
+	);
+	CREATE INDEX student_achievements_by_achievement ON student_achievements (achievement_id);`,
+	// Due dates are unix nanoseconds so they sort and compare as numbers.
+	`ALTER TABLE achievements ADD COLUMN due_date INTEGER;
+	CREATE INDEX achievements_by_due_date ON achievements (due_date);`,
+	`ALTER TABLE achievements ADD COLUMN points INTEGER NOT NULL DEFAULT 0;
+	CREATE TABLE points_ledger (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		student_id TEXT NOT NULL,
+		points INTEGER NOT NULL,
+		reason TEXT NOT NULL,
+		achievement_id TEXT NOT NULL DEFAULT '',
+		reward_id TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL
+	);
+	CREATE INDEX points_ledger_by_student ON points_ledger (student_id, achievement_id);
+	CREATE TABLE rewards (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		cost INTEGER NOT NULL
+	);`,
+	`ALTER TABLE achievements ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;`,
+	// student_achievements is kept as the latest state of the event log.
+	// Progress saved before the log existed is recorded as one event
+	// with no actor.
+	`CREATE TABLE progress_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		student_id TEXT NOT NULL,
+		achievement_id TEXT NOT NULL,
+		from_progress TEXT NOT NULL,
+		to_progress TEXT NOT NULL,
+		actor_id TEXT NOT NULL,
+		created_at INTEGER NOT NULL
+	);
+	CREATE INDEX progress_events_by_student ON progress_events (student_id, achievement_id);
+	INSERT INTO progress_events (student_id, achievement_id, from_progress, to_progress, actor_id, created_at)
+		SELECT student_id, achievement_id, '', progress, '', CAST(strftime('%s', 'now') AS INTEGER) * 1000000000
+		FROM student_achievements;`,
+	`ALTER TABLE progress_events ADD COLUMN comment TEXT NOT NULL DEFAULT '';`,
+	// Achievements created before creation times were recorded have 0,
+	// listing them first.
+	`ALTER TABLE achievements ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
+	CREATE INDEX achievements_by_created_at ON achievements (created_at, name, id);
+	CREATE INDEX achievements_by_name ON achievements (name, created_at, id);`,
+	`ALTER TABLE achievements ADD COLUMN category TEXT NOT NULL DEFAULT '';
+	CREATE INDEX achievements_by_category ON achievements (category);`,
+	`CREATE INDEX student_achievements_by_progress ON student_achievements (progress, student_id);`,
+}
+
+// NewSQLite returns an achievements.Store persisted in db, migrating
+// the schema to the latest version first.
+func NewSQLite(db *sql.DB) (achievements.Store, error) {
+	if err := database.Migrate(db, "achievements", migrations); err != nil {
+		return nil, err
+	}
+	return &sqlite{db: db}, nil
+}
+
+type sqlite struct {
+	db *sql.DB
+}
+
+const achievementColumns = `id, name, category, due_date, points, archived, created_at`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAchievement(row scanner) (achievements.Achievement, error) {
+	var a achievements.Achievement
+	var due sql.NullInt64
+	var created int64
+	if err := row.Scan(&a.ID, &a.Name, &a.Category, &due, &a.Points, &a.Archived, &created); err != nil {
+		return a, err
+	}
+	if due.Valid {
+		t := time.Unix(0, due.Int64).UTC()
+		a.DueDate = &t
+	}
+	if created != 0 {
+		a.CreatedAt = time.Unix(0, created).UTC()
+	}
+	return a, nil
+}
+
+func (s *sqlite) GetAchievement(ctx context.Context, id string) (*achievements.Achievement, error) {
+	a, err := scanAchievement(s.db.QueryRowContext(ctx, `SELECT `+achievementColumns+` FROM achievements WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, achievements.ErrAchievementNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (s *sqlite) GetAllAchievements(ctx context.Context) ([]achievements.Achievement, error) {
+	return s.ListAchievements(ctx, achievements.ListOptions{Filter: achievements.Filter{Status: achievements.StatusActive}})
+}
+
+func (s *sqlite) ListAchievements(ctx context.Context, opts achievements.ListOptions) ([]achievements.Achievement, error) {
+	keys := achievementListColumns.order(opts.Sort)
+	conds, args := achievementListColumns.where(opts.Filter)
+	if opts.After != "" {
+		// The cursor need not match the filter, such as when it has been
+		// archived since its page was listed.
+		cursor, err := scanCursor(s.db.QueryRowContext(ctx, `SELECT `+strings.Join(keys, ", ")+` FROM achievements WHERE id = ?`, opts.After), len(keys))
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, after(keys, opts.Descending))
+		args = append(args, cursor...)
+	}
+	query := `SELECT ` + achievementColumns + ` FROM achievements`
+	if len(conds) > 0 {
+		query += ` WHERE ` + strings.Join(conds, ` AND `)
+	}
+	rows, err := s.db.QueryContext(ctx, query+orderBy(keys, opts), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var aa []achievements.Achievement
+	for rows.Next() {
+		a, err := scanAchievement(rows)
+		if err != nil {
+			return nil, err
+		}
+		aa = append(aa, a)
+	}
+	return aa, rows.Err()
+}
+
+// listColumns name the expressions for what a listing sorts and filters
+// by. progress is empty when listing achievements themselves.
+type listColumns struct {
+	createdAt, name, category, dueDate, archived, id, progress string
+}
+
+var (
+	achievementListColumns = listColumns{createdAt: "created_at", name: "name", category: "category", dueDate: "due_date", archived: "archived", id: "id"}
+	// progressListColumns list progress by its achievement, which may
+	// be missing, as one with only an ID.
+	progressListColumns = listColumns{
+		createdAt: "COALESCE(a.created_at, 0)",
+		name:      "COALESCE(a.name, '')",
+		category:  "COALESCE(a.category, '')",
+		dueDate:   "a.due_date",
+		archived:  "COALESCE(a.archived, FALSE)",
+		id:        "sa.achievement_id",
+		progress:  "sa.progress",
+	}
+)
+
+// order returns the keys to sort by, matching ListOptions.Less.
+func (c listColumns) order(sort achievements.Sort) []string {
+	switch sort {
+	case achievements.SortName:
+		return []string{c.name, c.createdAt, c.id}
+	case achievements.SortDueDate:
+		return []string{`COALESCE(` + c.dueDate + `, ` + strconv.FormatInt(math.MaxInt64, 10) + `)`, c.createdAt, c.name, c.id}
+	default:
+		return []string{c.createdAt, c.name, c.id}
+	}
+}
+
+// where returns the conditions matching f, as Filter.Matches and
+// Filter.MatchesProgress do, and their arguments.
+func (c listColumns) where(f achievements.Filter) ([]string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	if f.Name != "" {
+		// lower only folds ASCII letters, just like Filter.Matches.
+		conds = append(conds, `instr(lower(`+c.name+`), lower(?)) > 0`)
+		args = append(args, f.Name)
+	}
+	if f.Category != "" {
+		conds = append(conds, c.category+` = ?`)
+		args = append(args, f.Category)
+	}
+	switch f.Status {
+	case achievements.StatusActive:
+		conds = append(conds, `NOT `+c.archived)
+	case achievements.StatusArchived:
+		conds = append(conds, c.archived)
+	}
+	if f.DueFrom != nil {
+		conds = append(conds, c.dueDate+` >= ?`)
+		args = append(args, f.DueFrom.UnixNano())
+	}
+	if f.DueBefore != nil {
+		conds = append(conds, c.dueDate+` < ?`)
+		args = append(args, f.DueBefore.UnixNano())
+	}
+	if c.progress != "" && len(f.Progress) > 0 {
+		conds = append(conds, c.progress+` IN (`+strings.Repeat("?, ", len(f.Progress)-1)+`?)`)
+		for _, p := range f.Progress {
+			args = append(args, p)
+		}
+	}
+	return conds, args
+}
+
+// scanCursor reads the keys of the row a listing carries on after.
+func scanCursor(row *sql.Row, n int) ([]interface{}, error) {
+	values := make([]interface{}, n)
+	dest := make([]interface{}, n)
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	err := row.Scan(dest...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, achievements.ErrUnknownCursor
+	}
+	return values, err
+}
+
+// after is a condition for rows which sort after the cursor, whose
+// keys are its arguments.
+func after(keys []string, descending bool) string {
+	cmp := ` > `
+	if descending {
+		cmp = ` < `
+	}
+	return `(` + strings.Join(keys, ", ") + `)` + cmp + `(` + strings.Repeat("?, ", len(keys)-1) + `?)`
+}
+
+func orderBy(keys []string, opts achievements.ListOptions) string {
+	dir := ` ASC`
+	if opts.Descending {
+		dir = ` DESC`
+	}
+	clause := ` ORDER BY ` + strings.Join(keys, dir+", ") + dir
+	if opts.Limit > 0 {
+		clause += ` LIMIT ` + strconv.Itoa(opts.Limit)
+	}
+	return clause
+}
+
+func (s *sqlite) UpdateAchievement(ctx context.Context, achievement achievements.Achievement) error {
+	var dueDate sql.NullInt64
+	if achievement.DueDate != nil {
+		dueDate = sql.NullInt64{Int64: achievement.DueDate.UnixNano(), Valid: true}
+	}
+	res, err := s.db.ExecContext(ctx, `UPDATE achievements SET name = ?, category = ?, due_date = ?, points = ? WHERE id = ? AND NOT archived`,
+		achievement.Name, achievement.Category, dueDate, achievement.Points, achievement.ID)
+	return expectUpdated(res, err)
+}
+
+func (s *sqlite) ArchiveAchievement(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `UPDATE achievements SET archived = TRUE WHERE id = ? AND NOT archived`, id)
+	return expectUpdated(res, err)
+}
+
+// expectUpdated turns an UPDATE of an achievement that matched no rows
+// into a not found error.
+func expectUpdated(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return achievements.ErrAchievementNotFound
+	}
+	return nil
+}
+
+func (s *sqlite) CreateAchievement(ctx context.Context, achievement achievements.Achievement) (string, error) {
+	id := gonanoid.Must()
+	var dueDate sql.NullInt64
+	if achievement.DueDate != nil {
+		dueDate = sql.NullInt64{Int64: achievement.DueDate.UnixNano(), Valid: true}
+	}
+	_, err := s.db.ExecContext(ctx, `INSERT INTO achievements (id, name, category, due_date, points, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		id, achievement.Name, achievement.Category, dueDate, achievement.Points, time.Now().UnixNano())
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *sqlite) SetDueDate(ctx context.Context, id string, due *time.Time) error {
+	var dueDate sql.NullInt64
+	if due != nil {
+		dueDate = sql.NullInt64{Int64: due.UnixNano(), Valid: true}
+	}
+	res, err := s.db.ExecContext(ctx, `UPDATE achievements SET due_date = ? WHERE id = ? AND NOT archived`, dueDate, id)
+	return expectUpdated(res, err)
+}
+
+func (s *sqlite) GetOverdueAchievements(ctx context.Context, now time.Time) ([]achievements.StudentAchievement, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT sa.student_id, sa.achievement_id, sa.progress
+		FROM student_achievements sa JOIN achievements a ON a.id = sa.achievement_id
+		WHERE a.due_date < ? AND sa.progress != ? AND NOT a.archived`, now.UnixNano(), achievements.Finished)
+	if err != nil {
+		return nil, err
+	}
+	return scanStudentAchievements(rows)
+}
+
+func (s *sqlite) AchievementExists(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM achievements WHERE id = ? AND NOT archived)`, id).Scan(&exists)
+	return exists, err
+}
+
+func (s *sqlite) GetStudentAchievements(ctx context.Context, studentID string) ([]achievements.StudentAchievement, error) {
+	return s.ListStudentAchievements(ctx, studentID, achievements.ListOptions{})
+}
+
+func (s *sqlite) ListStudentAchievements(ctx context.Context, studentID string, opts achievements.ListOptions) ([]achievements.StudentAchievement, error) {
+	progress := `student_achievements`
+	var fromArgs []interface{}
+	if opts.Filter.IncludesUnstarted() {
+		// Active achievements without progress are listed as if they had
+		// NotStarted progress.
+		progress = `(SELECT student_id, achievement_id, progress FROM student_achievements
+			UNION ALL
+			SELECT ?, id, ? FROM achievements WHERE NOT archived
+				AND id NOT IN (SELECT achievement_id FROM student_achievements WHERE student_id = ?))`
+		fromArgs = []interface{}{studentID, achievements.NotStarted, studentID}
+	}
+	from := ` FROM ` + progress + ` sa LEFT JOIN achievements a ON a.id = sa.achievement_id WHERE sa.student_id = ?`
+	fromArgs = append(fromArgs, studentID)
+	keys := progressListColumns.order(opts.Sort)
+	conds, args := progressListColumns.where(opts.Filter)
+	args = append(fromArgs, args...)
+	if opts.After != "" {
+		// The cursor need not match the filter either, so it is looked up
+		// among every achievement and all of the student's progress.
+		cursor, err := scanCursor(s.db.QueryRowContext(ctx, `SELECT `+strings.Join(keys, ", ")+`
+			FROM (SELECT ? AS student_id, ? AS achievement_id) sa LEFT JOIN achievements a ON a.id = sa.achievement_id
+			WHERE a.id IS NOT NULL
+				OR EXISTS (SELECT 1 FROM student_achievements WHERE student_id = sa.student_id AND achievement_id = sa.achievement_id)`,
+			studentID, opts.After), len(keys))
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, after(keys, opts.Descending))
+		args = append(args, cursor...)
+	}
+	query := `SELECT sa.student_id, sa.achievement_id, sa.progress` + from
+	for _, cond := range conds {
+		query += ` AND ` + cond
+	}
+	rows, err := s.db.QueryContext(ctx, query+orderBy(keys, opts), args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanStudentAchievements(rows)
+}
+
+func (s *sqlite) GetAchievementsForStudents(ctx context.Context, studentIDs []string) ([]achievements.StudentAchievement, error) {
+	if len(studentIDs) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, len(studentIDs))
+	for i, id := range studentIDs {
+		args[i] = id
+	}
+	placeholders := strings.Repeat("?, ", len(studentIDs)-1) + "?"
+	rows, err := s.db.QueryContext(ctx, `SELECT student_id, achievement_id, progress FROM student_achievements
+		WHERE student_id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanStudentAchievements(rows)
+}
+
+func (s *sqlite) GetStudentsByAchievement(ctx context.Context, id string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT student_id FROM student_achievements WHERE achievement_id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var students []string
+	for rows.Next() {
+		var student string
+		if err := rows.Scan(&student); err != nil {
+			return nil, err
+		}
+		students = append(students, student)
+	}
+	return students, rows.Err()
+}
+
+func (s *sqlite) AddProgression(ctx context.Context, progression achievements.StudentAchievement, actorID string) error {
+	return s.addProgression(ctx, progression, actorID, "", false)
+}
+
+func (s *sqlite) ReviewSubmission(ctx context.Context, review achievements.Review) error {
+	return s.addProgression(ctx, achievements.StudentAchievement{
+		AchievementID: review.AchievementID,
+		StudentID:     review.StudentID,
+		Progress:      review.Outcome(),
+	}, review.ReviewerID, review.Comment, true)
+}
+
+// addProgression appends to the progress event log, and saves the new
+// progress and any points it earns or loses, in a single transaction so
+// the log, current progress and ledger always agree. When onlySubmitted
+// is set, progress is only changed if it is currently Submitted.
+func (s *sqlite) addProgression(ctx context.Context, progression achievements.StudentAchievement, actorID string, comment string, onlySubmitted bool) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var previous achievements.Progress
+	err = tx.QueryRowContext(ctx, `SELECT progress FROM student_achievements WHERE student_id = ? AND achievement_id = ?`,
+		progression.StudentID, progression.AchievementID).Scan(&previous)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if onlySubmitted && previous != achievements.Submitted {
+		return achievements.ErrNotSubmitted
+	}
+	now := time.Now().UnixNano()
+	_, err = tx.ExecContext(ctx, `INSERT INTO progress_events (student_id, achievement_id, from_progress, to_progress, actor_id, created_at, comment)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		progression.StudentID, progression.AchievementID, previous, progression.Progress, actorID, now, comment)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO student_achievements (student_id, achievement_id, progress) VALUES (?, ?, ?)
+		ON CONFLICT (student_id, achievement_id) DO UPDATE SET progress = excluded.progress`,
+		progression.StudentID, progression.AchievementID, progression.Progress)
+	if err != nil {
+		return err
+	}
+
+	var worth, credited int
+	err = tx.QueryRowContext(ctx, `SELECT
+		COALESCE((SELECT points FROM achievements WHERE id = ?), 0),
+		COALESCE((SELECT SUM(points) FROM points_ledger WHERE student_id = ? AND achievement_id = ?), 0)`,
+		progression.AchievementID, progression.StudentID, progression.AchievementID).Scan(&worth, &credited)
+	if err != nil {
+		return err
+	}
+	points, reason := achievements.PointsChange(previous, progression.Progress, worth, credited)
+	if points != 0 {
+		_, err = tx.ExecContext(ctx, `INSERT INTO points_ledger (student_id, points, reason, achievement_id, created_at) VALUES (?, ?, ?, ?, ?)`,
+			progression.StudentID, points, reason, progression.AchievementID, now)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqlite) GetSubmissions(ctx context.Context, studentIDs []string) ([]achievements.Submission, error) {
+	if len(studentIDs) == 0 {
+		return nil, nil
+	}
+	args := []interface{}{achievements.Submitted}
+	for _, id := range studentIDs {
+		args = append(args, id)
+	}
+	placeholders := strings.Repeat("?, ", len(studentIDs)-1) + "?"
+	// The latest event of submitted progress is its submission.
+	rows, err := s.db.QueryContext(ctx, `SELECT sa.student_id, e.created_at, a.`+strings.ReplaceAll(achievementColumns, ", ", ", a.")+`
+		FROM student_achievements sa
+		JOIN achievements a ON a.id = sa.achievement_id
+		JOIN progress_events e ON e.id = (SELECT MAX(id) FROM progress_events
+			WHERE student_id = sa.student_id AND achievement_id = sa.achievement_id)
+		WHERE sa.progress = ? AND sa.student_id IN (`+placeholders+`) AND NOT a.archived
+		ORDER BY e.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var submissions []achievements.Submission
+	for rows.Next() {
+		var sub achievements.Submission
+		var submitted int64
+		sub.Achievement, err = scanAchievement(leadingColumns{rows, []interface{}{&sub.StudentID, &submitted}})
+		if err != nil {
+			return nil, err
+		}
+		sub.SubmittedAt = time.Unix(0, submitted).UTC()
+		submissions = append(submissions, sub)
+	}
+	return submissions, rows.Err()
+}
+
+// leadingColumns scans the columns before an achievement's into dest,
+// so the rest can be read by scanAchievement.
+type leadingColumns struct {
+	row  scanner
+	dest []interface{}
+}
+
+func (l leadingColumns) Scan(dest ...interface{}) error {
+	return l.row.Scan(append(l.dest, dest...)...)
+}
+
+func (s *sqlite) GetProgressHistory(ctx context.Context, studentID string, achievementID string) ([]achievements.ProgressEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT student_id, achievement_id, from_progress, to_progress, actor_id, created_at, comment
+		FROM progress_events WHERE student_id = ? AND achievement_id = ? ORDER BY id`, studentID, achievementID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var events []achievements.ProgressEvent
+	for rows.Next() {
+		var e achievements.ProgressEvent
+		var created int64
+		if err := rows.Scan(&e.StudentID, &e.AchievementID, &e.From, &e.To, &e.ActorID, &created, &e.Comment); err != nil {
+			return nil, err
+		}
+		e.Time = time.Unix(0, created).UTC()
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (s *sqlite) SetPoints(ctx context.Context, id string, points int) error {
+	res, err := s.db.ExecContext(ctx, `UPDATE achievements SET points = ? WHERE id = ? AND NOT archived`, points, id)
+	return expectUpdated(res, err)
+}
+
+func (s *sqlite) GetPointsLedger(ctx context.Context, studentID string) ([]achievements.PointsEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT student_id, points, reason, achievement_id, reward_id, created_at
+		FROM points_ledger WHERE student_id = ? ORDER BY id`, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var entries []achievements.PointsEntry
+	for rows.Next() {
+		var e achievements.PointsEntry
+		var created int64
+		if err := rows.Scan(&e.StudentID, &e.Points, &e.Reason, &e.AchievementID, &e.RewardID, &created); err != nil {
+			return nil, err
+		}
+		e.Time = time.Unix(0, created).UTC()
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (s *sqlite) GetPointsBalances(ctx context.Context, studentIDs []string) (map[string]int, error) {
+	balances := make(map[string]int, len(studentIDs))
+	if len(studentIDs) == 0 {
+		return balances, nil
+	}
+	args := make([]interface{}, len(studentIDs))
+	for i, id := range studentIDs {
+		balances[id] = 0
+		args[i] = id
+	}
+	placeholders := strings.Repeat("?, ", len(studentIDs)-1) + "?"
+	rows, err := s.db.QueryContext(ctx, `SELECT student_id, SUM(points) FROM points_ledger
+		WHERE student_id IN (`+placeholders+`) GROUP BY student_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var balance int
+		if err := rows.Scan(&id, &balance); err != nil {
+			return nil, err
+		}
+		balances[id] = balance
+	}
+	return balances, rows.Err()
+}
+
+func (s *sqlite) CreateReward(ctx context.Context, name string, cost int) (string, error) {
+	id := gonanoid.Must()
+	_, err := s.db.ExecContext(ctx, `INSERT INTO rewards (id, name, cost) VALUES (?, ?, ?)`, id, name, cost)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *sqlite) GetAllRewards(ctx context.Context) ([]achievements.Reward, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name, cost FROM rewards`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var rr []achievements.Reward
+	for rows.Next() {
+		var r achievements.Reward
+		if err := rows.Scan(&r.ID, &r.Name, &r.Cost); err != nil {
+			return nil, err
+		}
+		rr = append(rr, r)
+	}
+	return rr, rows.Err()
+}
+
+func (s *sqlite) RedeemReward(ctx context.Context, studentID string, rewardID string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var cost int
+	err = tx.QueryRowContext(ctx, `SELECT cost FROM rewards WHERE id = ?`, rewardID).Scan(&cost)
+	if errors.Is(err, sql.ErrNoRows) {
+		return achievements.ErrRewardNotFound
+	}
+	if err != nil {
+		return err
+	}
+	var balance int
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(SUM(points), 0) FROM points_ledger WHERE student_id = ?`, studentID).Scan(&balance)
+	if err != nil {
+		return err
+	}
+	if balance < cost {
+		return achievements.ErrInsufficientPoints
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO points_ledger (student_id, points, reason, reward_id, created_at) VALUES (?, ?, ?, ?, ?)`,
+		studentID, -cost, achievements.ReasonRewardRedeemed, rewardID, time.Now().UnixNano())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func scanStudentAchievements(rows *sql.Rows) ([]achievements.StudentAchievement, error) {
+	defer rows.Close()
+	var aa []achievements.StudentAchievement
+	for rows.Next() {
+		var sa achievements.StudentAchievement
+		if err := rows.Scan(&sa.StudentID, &sa.AchievementID, &sa.Progress); err != nil {
+			return nil, err
+		}
+		aa = append(aa, sa)
+	}
+	return aa, rows.Err()
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLitePersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "medlock.db")
+
+	db, err := database.Open(path)
+	require.NoError(t, err)
+	s, err := NewSQLite(db)
+	require.NoError(t, err)
+	id, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Start a Compost Heap"})
+	require.NoError(t, err)
+	require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{
+		AchievementID: id,
+		StudentID:     "student",
+		Progress:      achievements.Finished,
+	}, "teacher"))
+	require.NoError(t, db.Close())
+
+	db, err = database.Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+	// Opening an already migrated database must not re-run migrations.
+	s, err = NewSQLite(db)
+	require.NoError(t, err)
+
+	exists, err := s.AchievementExists(context.Background(), id)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	studentAchievements, err := s.GetStudentAchievements(context.Background(), "student")
+	require.NoError(t, err)
+	require.Len(t, studentAchievements, 1)
+	assert.Equal(t, achievements.Finished, studentAchievements[0].Progress)
+}
+
+func TestSQLiteMigrationRecordsExistingProgressAsEvents(t *testing.T) {
+	db, err := database.Open(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	// Set up a database as it was before the progress event log existed.
+	require.NoError(t, database.Migrate(db, "achievements", migrations[:4]))
+	_, err = db.Exec(`INSERT INTO student_achievements (student_id, achievement_id, progress) VALUES ('student', 'achievement', 'STARTED')`)
+	require.NoError(t, err)
+
+	s, err := NewSQLite(db)
+	require.NoError(t, err)
+	history, err := s.GetProgressHistory(context.Background(), "student", "achievement")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, achievements.NotStarted, history[0].From)
+	assert.Equal(t, achievements.Started, history[0].To)
+	current, err := s.GetStudentAchievements(context.Background(), "student")
+	require.NoError(t, err)
+	assert.Equal(t, current, achievements.Replay(history))
+}
+
+func TestSQLiteMigrationListsOlderAchievementsFirst(t *testing.T) {
+	db, err := database.Open(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	// Set up a database as it was before creation times were recorded.
+	require.NoError(t, database.Migrate(db, "achievements", migrations[:6]))
+	_, err = db.Exec(`INSERT INTO achievements (id, name) VALUES ('old', 'Zip a Coat')`)
+	require.NoError(t, err)
+
+	s, err := NewSQLite(db)
+	require.NoError(t, err)
+	id, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Adopt a Pet"})
+	require.NoError(t, err)
+	all, err := s.GetAllAchievements(context.Background())
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "old", all[0].ID)
+	assert.True(t, all[0].CreatedAt.IsZero())
+	assert.Equal(t, id, all[1].ID)
+}
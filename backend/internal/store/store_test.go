@@ -0,0 +1,29 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/database"
+	"github.com/Manchester-Dev/medlock/internal/storetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemory(t *testing.T) {
+	storetest.TestAchievementStore(t, func(t *testing.T) achievements.Store {
+		return NewInMemory()
+	})
+}
+
+func TestSQLite(t *testing.T) {
+	storetest.TestAchievementStore(t, newTestSQLite)
+}
+
+func newTestSQLite(t *testing.T) achievements.Store {
+	db, err := database.Open(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	s, err := NewSQLite(db)
+	require.NoError(t, err)
+	return s
+}
@@ -0,0 +1,269 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// accountExists reports whether store has an account with id, failing
+// the test if it cannot tell.
+func accountExists(t *testing.T, store account.Store, id string) bool {
+	t.Helper()
+	exists, err := store.AccountExists(context.Background(), id)
+	require.NoError(t, err)
+	return exists
+}
+
+// accountDetails are what an account.Account reports about itself, so
+// accounts from different implementations can be compared.
+type accountDetails struct {
+	ID, Name, Role, Code string
+}
+
+func details(accounts ...account.Account) []accountDetails {
+	d := make([]accountDetails, len(accounts))
+	for i, acc := range accounts {
+		d[i] = accountDetails{acc.ID(), acc.Name(), acc.Role(), acc.Code()}
+	}
+	return d
+}
+
+func assertSameAccount(t *testing.T, want account.Account, got account.Account) {
+	t.Helper()
+	assert.Equal(t, details(want), details(got))
+}
+
+// assertConflict, assertNoAccount and assertNoCode check for the error
+// types the web package tells apart, wrapped or not.
+func assertConflict(t *testing.T, err error) {
+	t.Helper()
+	var conflict account.CodeConflictError
+	assert.True(t, errors.As(err, &conflict), "want a CodeConflictError, got %v", err)
+}
+
+func assertNoAccount(t *testing.T, err error) {
+	t.Helper()
+	var noAccount *account.AccountDoesNotExistError
+	assert.True(t, errors.As(err, &noAccount), "want an *AccountDoesNotExistError, got %v", err)
+}
+
+func assertNoCode(t *testing.T, err error) {
+	t.Helper()
+	var noCode *account.CodeDoesNotExistError
+	assert.True(t, errors.As(err, &noCode), "want a *CodeDoesNotExistError, got %v", err)
+}
+
+func testSaveAccountConflict(t *testing.T, store account.Store) {
+	s := account.NewStudent("Student A")
+	err := store.SaveAccount(context.Background(), s)
+	require.NoError(t, err)
+
+	err = store.SaveAccount(context.Background(), s)
+	require.Error(t, err)
+	assertConflict(t, err)
+}
+
+func testSaveAccounts(t *testing.T, store account.Store) {
+	existing := account.NewStudent("Existing Student")
+	require.NoError(t, store.SaveAccount(context.Background(), existing))
+
+	t.Run("should save every account", func(t *testing.T) {
+		a, b := account.NewStudent("Student A"), account.NewStudent("Student B")
+		require.NoError(t, store.SaveAccounts(context.Background(), []account.Account{a, b}))
+		assert.True(t, accountExists(t, store, a.ID()))
+		assert.True(t, accountExists(t, store, b.ID()))
+	})
+
+	t.Run("should save none of the accounts when a code is in use", func(t *testing.T) {
+		fresh := account.NewStudent("Student C")
+		clash := account.New("clash", "Student D", account.RoleStudent, existing.Code())
+		err := store.SaveAccounts(context.Background(), []account.Account{fresh, clash})
+		assertConflict(t, err)
+		assert.False(t, accountExists(t, store, fresh.ID()))
+		assert.False(t, accountExists(t, store, clash.ID()))
+	})
+
+	t.Run("should save none of the accounts when a code is used twice", func(t *testing.T) {
+		first := account.New("first", "Student E", account.RoleStudent, "ZZZZ")
+		second := account.New("second", "Student F", account.RoleStudent, "ZZZZ")
+		err := store.SaveAccounts(context.Background(), []account.Account{first, second})
+		assertConflict(t, err)
+		assert.False(t, accountExists(t, store, first.ID()))
+	})
+}
+
+func testLogin(t *testing.T, store account.Store) {
+	s := account.NewStudent("Student A")
+	err := store.SaveAccount(context.Background(), s)
+	require.NoError(t, err)
+	loggedIn, err := store.Login(context.Background(), s.Code())
+	require.NoError(t, err)
+	assert.Equal(t, s.ID(), loggedIn.ID())
+	assert.Equal(t, s.Name(), loggedIn.Name())
+	assert.Equal(t, s.Role(), loggedIn.Role())
+	assert.Equal(t, s.Code(), loggedIn.Code())
+
+	_, err = store.Login(context.Background(), "not a code")
+	require.Error(t, err)
+	assertNoCode(t, err)
+}
+
+func testAccountExists(t *testing.T, store account.Store) {
+	s := account.NewStudent("Student A")
+	err := store.SaveAccount(context.Background(), s)
+	require.NoError(t, err)
+	exists, err := store.AccountExists(context.Background(), s.ID())
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = store.AccountExists(context.Background(), "this-doesn't-exist")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func testGetAccount(t *testing.T, store account.Store) {
+	s := account.NewStudent("Student A")
+	err := store.SaveAccount(context.Background(), s)
+	require.NoError(t, err)
+
+	found, err := store.GetAccount(context.Background(), s.ID())
+	require.NoError(t, err)
+	assertSameAccount(t, s, found)
+
+	_, err = store.GetAccount(context.Background(), "this-doesn't-exist")
+	require.Error(t, err)
+	assertNoAccount(t, err)
+}
+
+func testGetAccounts(t *testing.T, store account.Store) {
+	studentA := account.NewStudent("Student A")
+	studentB := account.NewStudent("Student B")
+	teacher := account.NewTeacher("Teacher A")
+	for _, acc := range []account.Account{studentA, studentB, teacher} {
+		require.NoError(t, store.SaveAccount(context.Background(), acc))
+	}
+
+	students, err := store.GetAccounts(context.Background(), account.RoleStudent)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, details(studentA, studentB), details(students...))
+
+	teachers, err := store.GetAccounts(context.Background(), account.RoleTeacher)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, details(teacher), details(teachers...))
+}
+
+func testRenameAccount(t *testing.T, store account.Store) {
+	s := account.NewStudent("Student A")
+	require.NoError(t, store.SaveAccount(context.Background(), s))
+
+	require.NoError(t, store.RenameAccount(context.Background(), s.ID(), "Student B"))
+	found, err := store.GetAccount(context.Background(), s.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "Student B", found.Name())
+	assert.Equal(t, s.Code(), found.Code())
+	loggedIn, err := store.Login(context.Background(), s.Code())
+	require.NoError(t, err)
+	assert.Equal(t, "Student B", loggedIn.Name())
+
+	err = store.RenameAccount(context.Background(), "this-doesn't-exist", "Student C")
+	assertNoAccount(t, err)
+}
+
+func testDeleteAccount(t *testing.T, store account.Store) {
+	s := account.NewStudent("Student A")
+	require.NoError(t, store.SaveAccount(context.Background(), s))
+
+	require.NoError(t, store.DeleteAccount(context.Background(), s.ID()))
+	assert.False(t, accountExists(t, store, s.ID()))
+	_, err := store.Login(context.Background(), s.Code())
+	assertNoCode(t, err)
+
+	err = store.DeleteAccount(context.Background(), s.ID())
+	assertNoAccount(t, err)
+}
+
+func testChangeCode(t *testing.T, store account.Store) {
+	s := account.NewStudent("Student A")
+	other := account.NewStudent("Student B")
+	require.NoError(t, store.SaveAccounts(context.Background(), []account.Account{s, other}))
+
+	updated, err := store.ChangeCode(context.Background(), s.ID(), "NEW1")
+	require.NoError(t, err)
+	assertSameAccount(t, account.New(s.ID(), s.Name(), s.Role(), "NEW1"), updated)
+
+	_, err = store.Login(context.Background(), s.Code())
+	// The old code stops working.
+	assertNoCode(t, err)
+	loggedIn, err := store.Login(context.Background(), "NEW1")
+	require.NoError(t, err)
+	assertSameAccount(t, updated, loggedIn)
+
+	_, err = store.ChangeCode(context.Background(), s.ID(), other.Code())
+	assertConflict(t, err)
+
+	_, err = store.ChangeCode(context.Background(), "this-doesn't-exist", "NEW2")
+	assertNoAccount(t, err)
+}
+
+func testCountAccounts(t *testing.T, store account.Store) {
+	n, err := store.CountAccounts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	require.NoError(t, store.SaveAccounts(context.Background(), []account.Account{account.NewStudent("Student A"), account.NewTeacher("Teacher A")}))
+	n, err = store.CountAccounts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
+
+func testPasswordHash(t *testing.T, store account.Store) {
+	teacher := account.NewTeacher("Teacher A")
+	require.NoError(t, store.SaveAccount(context.Background(), teacher))
+
+	hash, err := store.PasswordHash(context.Background(), teacher.ID())
+	require.NoError(t, err)
+	assert.Nil(t, hash)
+
+	require.NoError(t, store.SetPasswordHash(context.Background(), teacher.ID(), []byte("hash")))
+	hash, err = store.PasswordHash(context.Background(), teacher.ID())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hash"), hash)
+
+	require.NoError(t, store.SetPasswordHash(context.Background(), teacher.ID(), nil))
+	hash, err = store.PasswordHash(context.Background(), teacher.ID())
+	require.NoError(t, err)
+	assert.Nil(t, hash)
+
+	err = store.SetPasswordHash(context.Background(), "this-doesn't-exist", []byte("hash"))
+	assertNoAccount(t, err)
+	_, err = store.PasswordHash(context.Background(), "this-doesn't-exist")
+	assertNoAccount(t, err)
+}
+
+func testAccountErrorKinds(t *testing.T, store account.Store) {
+	s := account.NewStudent("Student A")
+	require.NoError(t, store.SaveAccount(context.Background(), s))
+
+	_, err := store.GetAccount(context.Background(), "this-doesn't-exist")
+	assert.ErrorIs(t, err, account.ErrNotFound)
+	_, err = store.Login(context.Background(), "this-doesn't-exist")
+	assert.ErrorIs(t, err, account.ErrNotFound)
+	err = store.SaveAccount(context.Background(), s)
+	assert.ErrorIs(t, err, account.ErrConflict)
+	assert.NotErrorIs(t, err, account.ErrNotFound)
+}
+
+func testAccountCancelledContext(t *testing.T, store account.Store) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := account.NewStudent("Student A")
+	err := store.SaveAccount(ctx, s)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = store.AccountExists(ctx, s.ID())
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, accountExists(t, store, s.ID()), "a cancelled save should not be kept")
+}
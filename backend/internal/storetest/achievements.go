@@ -0,0 +1,628 @@
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testGetStudentAchievements(t *testing.T, s achievements.Store) {
+	targetStudent := account.NewStudent("Target Student")
+	notTargetStudent := account.NewStudent("Not Target Student")
+
+	t.Run("should be empty when student is not progressing on any achievements", func(t *testing.T) {
+		studentAchievements, err := s.GetStudentAchievements(context.Background(), targetStudent.ID())
+		require.NoError(t, err)
+		assert.Empty(t, studentAchievements)
+	})
+
+	t.Run("should return the achievements assigned to a student", func(t *testing.T) {
+		aID := gonanoid.Must()
+		require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{
+			StudentID:     targetStudent.ID(),
+			AchievementID: aID,
+			Progress:      achievements.Started,
+		}, "teacher"))
+		require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{
+			StudentID:     notTargetStudent.ID(),
+			AchievementID: aID,
+			Progress:      achievements.Started,
+		}, "teacher"))
+		studentAchievements, err := s.GetStudentAchievements(context.Background(), targetStudent.ID())
+		require.NoError(t, err)
+		require.Len(t, studentAchievements, 1)
+		ach := studentAchievements[0]
+		assert.Equal(t, targetStudent.ID(), ach.StudentID)
+	})
+}
+
+func testGetAchievementsForStudents(t *testing.T, s achievements.Store) {
+	t.Run("should be empty when given no students", func(t *testing.T) {
+		studentAchievements, err := s.GetAchievementsForStudents(context.Background(), nil)
+		require.NoError(t, err)
+		assert.Empty(t, studentAchievements)
+	})
+
+	t.Run("should return the achievements of every given student only", func(t *testing.T) {
+		aID := gonanoid.Must()
+		a := achievements.StudentAchievement{StudentID: "student-a", AchievementID: aID, Progress: achievements.Started}
+		b := achievements.StudentAchievement{StudentID: "student-b", AchievementID: aID, Progress: achievements.Finished}
+		c := achievements.StudentAchievement{StudentID: "student-c", AchievementID: aID, Progress: achievements.Started}
+		require.NoError(t, s.AddProgression(context.Background(), a, "teacher"))
+		require.NoError(t, s.AddProgression(context.Background(), b, "teacher"))
+		require.NoError(t, s.AddProgression(context.Background(), c, "teacher"))
+
+		studentAchievements, err := s.GetAchievementsForStudents(context.Background(), []string{"student-a", "student-b", "student-d"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []achievements.StudentAchievement{a, b}, studentAchievements)
+	})
+}
+
+func testGetStudentsByAchievement(t *testing.T, s achievements.Store) {
+	t.Run("should return empty when there are no students progressing with the achievement", func(t *testing.T) {
+		aID := gonanoid.Must()
+		all, err := s.GetStudentsByAchievement(context.Background(), aID)
+		require.NoError(t, err)
+		assert.Empty(t, all)
+	})
+
+	t.Run("should return all students which are progressing on a particular achievement", func(t *testing.T) {
+		student := account.NewStudent("Test Student")
+		aID := gonanoid.Must()
+		progressing := achievements.StudentAchievement{
+			StudentID:     student.ID(),
+			AchievementID: aID,
+			Progress:      achievements.Started,
+		}
+
+		require.NoError(t, s.AddProgression(context.Background(), progressing, "teacher"))
+		all, err := s.GetStudentsByAchievement(context.Background(), aID)
+		require.NoError(t, err)
+
+		require.Len(t, all, 1)
+		assert.Equal(t, student.ID(), all[0])
+
+		student2 := account.NewStudent("Test Student")
+		require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{
+			AchievementID: aID,
+			StudentID:     student2.ID(),
+			Progress:      achievements.Started,
+		}, "teacher"))
+		studentNotProgressingAchievement := account.NewStudent("Test Student")
+		require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{
+			AchievementID: "a123",
+			StudentID:     studentNotProgressingAchievement.ID(),
+			Progress:      achievements.Started,
+		}, "teacher"))
+		all, err = s.GetStudentsByAchievement(context.Background(), aID)
+		require.NoError(t, err)
+
+		require.Len(t, all, 2)
+		assert.Contains(t, all, student.ID())
+		assert.Contains(t, all, student2.ID())
+		assert.NotContains(t, all, studentNotProgressingAchievement.ID())
+	})
+}
+
+func testAddProgressionOverwrites(t *testing.T, s achievements.Store) {
+	t.Run("should update a student's progression on a particular achievement", func(t *testing.T) {
+		student := account.NewStudent("Test Student")
+		aID := gonanoid.Must()
+		require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{
+			StudentID:     student.ID(),
+			AchievementID: aID,
+		}, "teacher"))
+		updated := achievements.StudentAchievement{
+			StudentID:     student.ID(),
+			AchievementID: aID,
+			Progress:      achievements.Started,
+		}
+		require.NoError(t, s.AddProgression(context.Background(), updated, "teacher"))
+
+		studentAchievements, err := s.GetStudentAchievements(context.Background(), student.ID())
+		require.NoError(t, err)
+		require.Len(t, studentAchievements, 1)
+		assert.Equal(t, updated, studentAchievements[0])
+	})
+}
+
+func testAchievementExists(t *testing.T, s achievements.Store) {
+	t.Run("should return true for existing achievement", func(t *testing.T) {
+		ach := "THIS_IS_AN_ACHIEVEMENT"
+		id, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: ach})
+		require.NoError(t, err)
+		exists, err := s.AchievementExists(context.Background(), id)
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("should return false for unknown achievement", func(t *testing.T) {
+		exists, err := s.AchievementExists(context.Background(), "non-existent-id")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
+
+func testGetAchievement(t *testing.T, s achievements.Store) {
+	t.Run("should throw an error if achievement does not exist", func(t *testing.T) {
+		_, err := s.GetAchievement(context.Background(), "non-existent-id")
+		assert.ErrorIs(t, err, achievements.ErrAchievementNotFound)
+	})
+
+	t.Run("should return a created achievement", func(t *testing.T) {
+		id, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Plant some Seeds"})
+		require.NoError(t, err)
+		a, err := s.GetAchievement(context.Background(), id)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), a.CreatedAt, time.Minute)
+		a.CreatedAt = time.Time{}
+		assert.Equal(t, achievements.Achievement{ID: id, Name: "Plant some Seeds"}, *a)
+	})
+
+	t.Run("should create an achievement with all its details", func(t *testing.T) {
+		due := time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)
+		id, err := s.CreateAchievement(context.Background(), achievements.Achievement{
+			ID:       "ignored",
+			Name:     "Fix a Broken Toy",
+			Category: "Repairs",
+			DueDate:  &due,
+			Points:   5,
+			Archived: true,
+		})
+		require.NoError(t, err)
+		assert.NotEqual(t, "ignored", id)
+		a, err := s.GetAchievement(context.Background(), id)
+		require.NoError(t, err)
+		a.CreatedAt = time.Time{}
+		assert.Equal(t, achievements.Achievement{ID: id, Name: "Fix a Broken Toy", Category: "Repairs", DueDate: &due, Points: 5}, *a)
+	})
+}
+
+func testGetAllAchievements(t *testing.T, s achievements.Store) {
+	t.Run("should be empty when no achievements have been created", func(t *testing.T) {
+		all, err := s.GetAllAchievements(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, all)
+	})
+
+	t.Run("should return every created achievement", func(t *testing.T) {
+		id1, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Fix a Broken Toy"})
+		require.NoError(t, err)
+		id2, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Donate Old Clothing"})
+		require.NoError(t, err)
+		all, err := s.GetAllAchievements(context.Background())
+		require.NoError(t, err)
+		for i := range all {
+			all[i].CreatedAt = time.Time{}
+		}
+		assert.ElementsMatch(t, []achievements.Achievement{
+			{ID: id1, Name: "Fix a Broken Toy"},
+			{ID: id2, Name: "Donate Old Clothing"},
+		}, all)
+	})
+}
+
+func testSetDueDate(t *testing.T, s achievements.Store) {
+	id, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Recycle 10 Batteries"})
+	require.NoError(t, err)
+
+	t.Run("should have no due date by default", func(t *testing.T) {
+		a, err := s.GetAchievement(context.Background(), id)
+		require.NoError(t, err)
+		assert.Nil(t, a.DueDate)
+	})
+
+	t.Run("should set the due date of an achievement", func(t *testing.T) {
+		due := time.Date(2022, time.March, 4, 15, 30, 0, 0, time.UTC)
+		require.NoError(t, s.SetDueDate(context.Background(), id, &due))
+		a, err := s.GetAchievement(context.Background(), id)
+		require.NoError(t, err)
+		require.NotNil(t, a.DueDate)
+		assert.True(t, due.Equal(*a.DueDate))
+		all, err := s.GetAllAchievements(context.Background())
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.NotNil(t, all[0].DueDate)
+		assert.True(t, due.Equal(*all[0].DueDate))
+	})
+
+	t.Run("should clear the due date", func(t *testing.T) {
+		require.NoError(t, s.SetDueDate(context.Background(), id, nil))
+		a, err := s.GetAchievement(context.Background(), id)
+		require.NoError(t, err)
+		assert.Nil(t, a.DueDate)
+	})
+
+	t.Run("should return an error for unknown achievements", func(t *testing.T) {
+		err := s.SetDueDate(context.Background(), "non-existent-id", nil)
+		assert.ErrorIs(t, err, achievements.ErrAchievementNotFound)
+	})
+}
+
+func testGetOverdueAchievements(t *testing.T, s achievements.Store) {
+	now := time.Date(2022, time.March, 4, 12, 0, 0, 0, time.UTC)
+	yesterday := now.Add(-24 * time.Hour)
+	tomorrow := now.Add(24 * time.Hour)
+	overdue, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Fix a Broken Toy"})
+	require.NoError(t, err)
+	require.NoError(t, s.SetDueDate(context.Background(), overdue, &yesterday))
+	upcoming, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Plant some Seeds"})
+	require.NoError(t, err)
+	require.NoError(t, s.SetDueDate(context.Background(), upcoming, &tomorrow))
+	noDeadline, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Donate Old Clothing"})
+	require.NoError(t, err)
+
+	late := achievements.StudentAchievement{AchievementID: overdue, StudentID: "student-a", Progress: achievements.Started}
+	notStarted := achievements.StudentAchievement{AchievementID: overdue, StudentID: "student-b", Progress: achievements.NotStarted}
+	for _, sa := range []achievements.StudentAchievement{
+		late,
+		notStarted,
+		{AchievementID: overdue, StudentID: "student-c", Progress: achievements.Finished},
+		{AchievementID: upcoming, StudentID: "student-a", Progress: achievements.Started},
+		{AchievementID: noDeadline, StudentID: "student-a", Progress: achievements.Started},
+	} {
+		require.NoError(t, s.AddProgression(context.Background(), sa, "teacher"))
+	}
+
+	aa, err := s.GetOverdueAchievements(context.Background(), now)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []achievements.StudentAchievement{late, notStarted}, aa)
+}
+
+func testPoints(t *testing.T, s achievements.Store) {
+	id, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Start a Compost Heap"})
+	require.NoError(t, err)
+	require.NoError(t, s.SetPoints(context.Background(), id, 10))
+	progress := func(p achievements.Progress) {
+		require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{AchievementID: id, StudentID: "student", Progress: p}, "teacher"))
+	}
+	balance := func(t *testing.T) int {
+		balances, err := s.GetPointsBalances(context.Background(), []string{"student"})
+		require.NoError(t, err)
+		return balances["student"]
+	}
+
+	t.Run("should store the points an achievement is worth", func(t *testing.T) {
+		a, err := s.GetAchievement(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, 10, a.Points)
+		assert.Error(t, s.SetPoints(context.Background(), "non-existent-id", 10))
+	})
+
+	t.Run("should not credit points until the achievement is finished", func(t *testing.T) {
+		progress(achievements.Started)
+		assert.Zero(t, balance(t))
+	})
+
+	t.Run("should credit points when the achievement is finished", func(t *testing.T) {
+		progress(achievements.Finished)
+		progress(achievements.Finished)
+		assert.Equal(t, 10, balance(t))
+	})
+
+	t.Run("should reverse the credit when moving back from finished", func(t *testing.T) {
+		// Changing what the achievement is worth must not change
+		// what gets reversed.
+		require.NoError(t, s.SetPoints(context.Background(), id, 25))
+		progress(achievements.Started)
+		assert.Zero(t, balance(t))
+	})
+
+	t.Run("should keep a ledger of every change", func(t *testing.T) {
+		progress(achievements.Finished)
+		ledger, err := s.GetPointsLedger(context.Background(), "student")
+		require.NoError(t, err)
+		require.Len(t, ledger, 3)
+		assert.Equal(t, []int{10, -10, 25}, []int{ledger[0].Points, ledger[1].Points, ledger[2].Points})
+		assert.Equal(t, achievements.ReasonAchievementFinished, ledger[0].Reason)
+		assert.Equal(t, achievements.ReasonAchievementReopened, ledger[1].Reason)
+		assert.Equal(t, id, ledger[2].AchievementID)
+		assert.False(t, ledger[2].Time.IsZero())
+
+		empty, err := s.GetPointsLedger(context.Background(), "someone-else")
+		require.NoError(t, err)
+		assert.Empty(t, empty)
+	})
+
+	t.Run("should return a zero balance for students without points", func(t *testing.T) {
+		balances, err := s.GetPointsBalances(context.Background(), []string{"student", "someone-else"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"student": 25, "someone-else": 0}, balances)
+	})
+}
+
+func testRewards(t *testing.T, s achievements.Store) {
+	id, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Start a Compost Heap"})
+	require.NoError(t, err)
+	require.NoError(t, s.SetPoints(context.Background(), id, 10))
+	require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{AchievementID: id, StudentID: "student", Progress: achievements.Finished}, "teacher"))
+	sticker, err := s.CreateReward(context.Background(), "Sticker", 4)
+	require.NoError(t, err)
+	treat, err := s.CreateReward(context.Background(), "Golden Ticket", 50)
+	require.NoError(t, err)
+
+	t.Run("should list every reward", func(t *testing.T) {
+		rewards, err := s.GetAllRewards(context.Background())
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []achievements.Reward{
+			{ID: sticker, Name: "Sticker", Cost: 4},
+			{ID: treat, Name: "Golden Ticket", Cost: 50},
+		}, rewards)
+	})
+
+	t.Run("should spend points on a reward", func(t *testing.T) {
+		require.NoError(t, s.RedeemReward(context.Background(), "student", sticker))
+		require.NoError(t, s.RedeemReward(context.Background(), "student", sticker))
+		balances, err := s.GetPointsBalances(context.Background(), []string{"student"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, balances["student"])
+		ledger, err := s.GetPointsLedger(context.Background(), "student")
+		require.NoError(t, err)
+		require.Len(t, ledger, 3)
+		assert.Equal(t, achievements.ReasonRewardRedeemed, ledger[2].Reason)
+		assert.Equal(t, sticker, ledger[2].RewardID)
+		assert.Equal(t, -4, ledger[2].Points)
+	})
+
+	t.Run("should refuse a reward the student cannot afford", func(t *testing.T) {
+		err := s.RedeemReward(context.Background(), "student", sticker)
+		assert.ErrorIs(t, err, achievements.ErrInsufficientPoints)
+	})
+
+	t.Run("should return an error for unknown rewards", func(t *testing.T) {
+		err := s.RedeemReward(context.Background(), "student", "non-existent-id")
+		assert.ErrorIs(t, err, achievements.ErrRewardNotFound)
+	})
+}
+
+func testUpdateAchievement(t *testing.T, s achievements.Store) {
+	id, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Plnat some Seeds"})
+	require.NoError(t, err)
+
+	t.Run("should change an achievement's details", func(t *testing.T) {
+		due := time.Date(2022, time.May, 1, 9, 0, 0, 0, time.UTC)
+		err := s.UpdateAchievement(context.Background(), achievements.Achievement{ID: id, Name: "Plant some Seeds", Category: "Gardening", DueDate: &due, Points: 5})
+		require.NoError(t, err)
+		a, err := s.GetAchievement(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, "Plant some Seeds", a.Name)
+		assert.Equal(t, "Gardening", a.Category)
+		assert.Equal(t, 5, a.Points)
+		require.NotNil(t, a.DueDate)
+		assert.True(t, due.Equal(*a.DueDate))
+	})
+
+	t.Run("should return an error for unknown achievements", func(t *testing.T) {
+		err := s.UpdateAchievement(context.Background(), achievements.Achievement{ID: "non-existent-id", Name: "name"})
+		assert.ErrorIs(t, err, achievements.ErrAchievementNotFound)
+	})
+}
+
+func testArchiveAchievement(t *testing.T, s achievements.Store) {
+	kept, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Fix a Broken Toy"})
+	require.NoError(t, err)
+	archived, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Donate Old Clothing"})
+	require.NoError(t, err)
+	require.NoError(t, s.SetDueDate(context.Background(), archived, &time.Time{}))
+	progression := achievements.StudentAchievement{AchievementID: archived, StudentID: "student", Progress: achievements.Started}
+	require.NoError(t, s.AddProgression(context.Background(), progression, "teacher"))
+	require.NoError(t, s.ArchiveAchievement(context.Background(), archived))
+
+	t.Run("should drop archived achievements from the list of all achievements", func(t *testing.T) {
+		all, err := s.GetAllAchievements(context.Background())
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		assert.Equal(t, kept, all[0].ID)
+		exists, err := s.AchievementExists(context.Background(), archived)
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("should keep archived achievements and students' progress on them", func(t *testing.T) {
+		a, err := s.GetAchievement(context.Background(), archived)
+		require.NoError(t, err)
+		assert.True(t, a.Archived)
+		studentAchievements, err := s.GetStudentAchievements(context.Background(), "student")
+		require.NoError(t, err)
+		assert.Equal(t, []achievements.StudentAchievement{progression}, studentAchievements)
+	})
+
+	t.Run("should not report archived achievements as overdue", func(t *testing.T) {
+		overdue, err := s.GetOverdueAchievements(context.Background(), time.Now())
+		require.NoError(t, err)
+		assert.Empty(t, overdue)
+	})
+
+	t.Run("should not change archived achievements", func(t *testing.T) {
+		assert.Error(t, s.ArchiveAchievement(context.Background(), archived))
+		assert.Error(t, s.UpdateAchievement(context.Background(), achievements.Achievement{ID: archived, Name: "name"}))
+		assert.Error(t, s.SetPoints(context.Background(), archived, 1))
+		assert.Error(t, s.SetDueDate(context.Background(), archived, nil))
+		assert.Error(t, s.ArchiveAchievement(context.Background(), "non-existent-id"))
+	})
+}
+
+func testGetProgressHistory(t *testing.T, s achievements.Store) {
+	aID := gonanoid.Must()
+	before := time.Now()
+	require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{AchievementID: aID, StudentID: "student", Progress: achievements.NotStarted}, "teacher"))
+	require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{AchievementID: aID, StudentID: "student", Progress: achievements.Started}, "student"))
+	require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{AchievementID: "other", StudentID: "student", Progress: achievements.Started}, "student"))
+	require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{AchievementID: aID, StudentID: "student", Progress: achievements.Finished}, "teacher"))
+
+	t.Run("should return every change in order", func(t *testing.T) {
+		history, err := s.GetProgressHistory(context.Background(), "student", aID)
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+		type change struct {
+			from, to achievements.Progress
+			actor    string
+		}
+		var changes []change
+		for _, e := range history {
+			assert.Equal(t, "student", e.StudentID)
+			assert.Equal(t, aID, e.AchievementID)
+			assert.False(t, e.Time.Before(before.Truncate(time.Second)))
+			changes = append(changes, change{e.From, e.To, e.ActorID})
+		}
+		assert.Equal(t, []change{
+			{achievements.NotStarted, achievements.NotStarted, "teacher"},
+			{achievements.NotStarted, achievements.Started, "student"},
+			{achievements.Started, achievements.Finished, "teacher"},
+		}, changes)
+	})
+
+	t.Run("should rebuild current progress from the history", func(t *testing.T) {
+		history, err := s.GetProgressHistory(context.Background(), "student", aID)
+		require.NoError(t, err)
+		other, err := s.GetProgressHistory(context.Background(), "student", "other")
+		require.NoError(t, err)
+		current, err := s.GetStudentAchievements(context.Background(), "student")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, current, achievements.Replay(append(history, other...)))
+	})
+
+	t.Run("should be empty for achievements without progress", func(t *testing.T) {
+		history, err := s.GetProgressHistory(context.Background(), "student", "no-progress")
+		require.NoError(t, err)
+		assert.Empty(t, history)
+	})
+}
+
+func testReviewSubmission(t *testing.T, s achievements.Store) {
+	aID, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Plant some Seeds"})
+	require.NoError(t, err)
+	require.NoError(t, s.SetPoints(context.Background(), aID, 5))
+
+	t.Run("should only review submitted achievements", func(t *testing.T) {
+		require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{AchievementID: aID, StudentID: "student", Progress: achievements.Started}, "student"))
+		err := s.ReviewSubmission(context.Background(), achievements.Review{StudentID: "student", AchievementID: aID, Approved: true, ReviewerID: "teacher"})
+		assert.Equal(t, achievements.ErrNotSubmitted, err)
+		err = s.ReviewSubmission(context.Background(), achievements.Review{StudentID: "nobody", AchievementID: aID, Approved: true, ReviewerID: "teacher"})
+		assert.Equal(t, achievements.ErrNotSubmitted, err)
+	})
+
+	t.Run("should send a rejected submission back to started with the comment", func(t *testing.T) {
+		require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{AchievementID: aID, StudentID: "student", Progress: achievements.Submitted}, "student"))
+		err := s.ReviewSubmission(context.Background(), achievements.Review{StudentID: "student", AchievementID: aID, Comment: "Needs a photo", ReviewerID: "teacher"})
+		require.NoError(t, err)
+		aa, err := s.GetStudentAchievements(context.Background(), "student")
+		require.NoError(t, err)
+		assert.Equal(t, []achievements.StudentAchievement{{AchievementID: aID, StudentID: "student", Progress: achievements.Started}}, aa)
+		history, err := s.GetProgressHistory(context.Background(), "student", aID)
+		require.NoError(t, err)
+		last := history[len(history)-1]
+		assert.Equal(t, achievements.Submitted, last.From)
+		assert.Equal(t, "teacher", last.ActorID)
+		assert.Equal(t, "Needs a photo", last.Comment)
+	})
+
+	t.Run("should finish an approved submission and credit its points", func(t *testing.T) {
+		require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{AchievementID: aID, StudentID: "student", Progress: achievements.Submitted}, "student"))
+		err := s.ReviewSubmission(context.Background(), achievements.Review{StudentID: "student", AchievementID: aID, Approved: true, ReviewerID: "teacher"})
+		require.NoError(t, err)
+		aa, err := s.GetStudentAchievements(context.Background(), "student")
+		require.NoError(t, err)
+		assert.Equal(t, []achievements.StudentAchievement{{AchievementID: aID, StudentID: "student", Progress: achievements.Finished}}, aa)
+		balances, err := s.GetPointsBalances(context.Background(), []string{"student"})
+		require.NoError(t, err)
+		assert.Equal(t, 5, balances["student"])
+	})
+}
+
+func testGetSubmissions(t *testing.T, s achievements.Store) {
+	ctx := context.Background()
+	first, err := s.CreateAchievement(ctx, achievements.Achievement{Name: "Plant some Seeds"})
+	require.NoError(t, err)
+	second, err := s.CreateAchievement(ctx, achievements.Achievement{Name: "Fix a Broken Toy"})
+	require.NoError(t, err)
+	archived, err := s.CreateAchievement(ctx, achievements.Achievement{Name: "Start a Compost Heap"})
+	require.NoError(t, err)
+	for _, p := range []achievements.StudentAchievement{
+		{AchievementID: second, StudentID: "student-a", Progress: achievements.Started},
+		{AchievementID: first, StudentID: "student-b", Progress: achievements.Submitted},
+		{AchievementID: second, StudentID: "student-a", Progress: achievements.Submitted},
+		{AchievementID: first, StudentID: "student-a", Progress: achievements.Submitted},
+		{AchievementID: archived, StudentID: "student-a", Progress: achievements.Submitted},
+		{AchievementID: "not-an-achievement", StudentID: "student-a", Progress: achievements.Submitted},
+		{AchievementID: second, StudentID: "student-b", Progress: achievements.Finished},
+		{AchievementID: first, StudentID: "student-c", Progress: achievements.Submitted},
+	} {
+		require.NoError(t, s.AddProgression(ctx, p, p.StudentID))
+	}
+	require.NoError(t, s.ArchiveAchievement(ctx, archived))
+
+	t.Run("should list the given students' submissions, oldest first", func(t *testing.T) {
+		submissions, err := s.GetSubmissions(ctx, []string{"student-a", "student-b", "student-d"})
+		require.NoError(t, err)
+		require.Len(t, submissions, 3)
+		for i, want := range []struct{ student, achievement string }{
+			{"student-b", first},
+			{"student-a", second},
+			{"student-a", first},
+		} {
+			assert.Equal(t, want.student, submissions[i].StudentID)
+			assert.Equal(t, want.achievement, submissions[i].Achievement.ID)
+		}
+		assert.Equal(t, "Fix a Broken Toy", submissions[1].Achievement.Name)
+		history, err := s.GetProgressHistory(ctx, "student-a", first)
+		require.NoError(t, err)
+		assert.True(t, history[len(history)-1].Time.Equal(submissions[2].SubmittedAt))
+	})
+
+	t.Run("should drop a submission once it is reviewed", func(t *testing.T) {
+		require.NoError(t, s.ReviewSubmission(ctx, achievements.Review{StudentID: "student-c", AchievementID: first, Approved: true, ReviewerID: "teacher"}))
+		submissions, err := s.GetSubmissions(ctx, []string{"student-c"})
+		require.NoError(t, err)
+		assert.Empty(t, submissions)
+	})
+
+	t.Run("should be empty when given no students", func(t *testing.T) {
+		submissions, err := s.GetSubmissions(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, submissions)
+	})
+}
+
+func testErrorKinds(t *testing.T, s achievements.Store) {
+	t.Run("should report missing achievements and rewards as not found", func(t *testing.T) {
+		_, err := s.GetAchievement(context.Background(), "non-existent-id")
+		assert.ErrorIs(t, err, achievements.ErrNotFound)
+		err = s.RedeemReward(context.Background(), "student", "non-existent-id")
+		assert.ErrorIs(t, err, achievements.ErrNotFound)
+	})
+
+	t.Run("should report reviews and redemptions that do not apply as conflicts", func(t *testing.T) {
+		err := s.ReviewSubmission(context.Background(), achievements.Review{StudentID: "student", AchievementID: "non-existent-id", ReviewerID: "teacher"})
+		assert.ErrorIs(t, err, achievements.ErrConflict)
+		reward, err := s.CreateReward(context.Background(), "Sticker", 1)
+		require.NoError(t, err)
+		err = s.RedeemReward(context.Background(), "student", reward)
+		assert.ErrorIs(t, err, achievements.ErrConflict)
+	})
+}
+
+func testCancelledContext(t *testing.T, s achievements.Store) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.CreateAchievement(ctx, achievements.Achievement{Name: "Plant some Seeds"})
+	assert.ErrorIs(t, err, context.Canceled)
+	err = s.AddProgression(ctx, achievements.StudentAchievement{AchievementID: "achievement", StudentID: "student", Progress: achievements.Started}, "teacher")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = s.GetAllAchievements(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	all, err := s.GetAllAchievements(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, all, "a cancelled call should not change anything")
+	aa, err := s.GetStudentAchievements(context.Background(), "student")
+	require.NoError(t, err)
+	assert.Empty(t, aa, "a cancelled call should not change anything")
+}
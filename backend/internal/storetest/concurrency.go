@@ -0,0 +1,164 @@
+package storetest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConcurrentProgressions(t *testing.T, s achievements.Store) {
+	id, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Plant some Seeds"})
+	require.NoError(t, err)
+	require.NoError(t, s.SetPoints(context.Background(), id, 10))
+	const students, updates = 8, 50
+
+	var wg sync.WaitGroup
+	for n := 0; n < students; n++ {
+		wg.Add(1)
+		go func(studentID string) {
+			defer wg.Done()
+			for j := 0; j < updates; j++ {
+				progress := achievements.Started
+				if j%2 == 1 {
+					progress = achievements.Finished
+				}
+				assert.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{
+					AchievementID: id,
+					StudentID:     studentID,
+					Progress:      progress,
+				}, "teacher"))
+				_, err := s.GetStudentAchievements(context.Background(), studentID)
+				assert.NoError(t, err)
+				_, err = s.GetPointsBalances(context.Background(), []string{studentID})
+				assert.NoError(t, err)
+				_, err = s.GetAllAchievements(context.Background())
+				assert.NoError(t, err)
+			}
+		}(fmt.Sprintf("student-%d", n))
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < updates; j++ {
+			other, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: fmt.Sprintf("Achievement %d", j)})
+			assert.NoError(t, err)
+			assert.NoError(t, s.ArchiveAchievement(context.Background(), other))
+			_, err = s.GetStudentsByAchievement(context.Background(), id)
+			assert.NoError(t, err)
+		}
+	}()
+	wg.Wait()
+
+	for n := 0; n < students; n++ {
+		studentID := fmt.Sprintf("student-%d", n)
+		aa, err := s.GetStudentAchievements(context.Background(), studentID)
+		require.NoError(t, err)
+		require.Len(t, aa, 1)
+		assert.Equal(t, achievements.Finished, aa[0].Progress)
+		history, err := s.GetProgressHistory(context.Background(), studentID, id)
+		require.NoError(t, err)
+		assert.Len(t, history, updates)
+		balances, err := s.GetPointsBalances(context.Background(), []string{studentID})
+		require.NoError(t, err)
+		assert.Equal(t, 10, balances[studentID], "points should match the final progress")
+	}
+}
+
+func testConcurrentRedemptions(t *testing.T, s achievements.Store) {
+	id, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Plant some Seeds"})
+	require.NoError(t, err)
+	require.NoError(t, s.SetPoints(context.Background(), id, 10))
+	require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{AchievementID: id, StudentID: "student", Progress: achievements.Finished}, "teacher"))
+	reward, err := s.CreateReward(context.Background(), "Sticker", 3)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	redeemed := 0
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := s.RedeemReward(context.Background(), "student", reward)
+			if err == nil {
+				mu.Lock()
+				redeemed++
+				mu.Unlock()
+				return
+			}
+			assert.ErrorIs(t, err, achievements.ErrInsufficientPoints)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 3, redeemed, "only as many as the balance covers")
+	balances, err := s.GetPointsBalances(context.Background(), []string{"student"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, balances["student"])
+}
+
+func testConcurrentSavesAndLogins(t *testing.T, store account.Store) {
+	const workers, accounts = 8, 25
+
+	var wg sync.WaitGroup
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for j := 0; j < accounts; j++ {
+				acc := account.New(fmt.Sprintf("student-%d-%d", n, j), "Student", account.RoleStudent, fmt.Sprintf("W%dA%d", n, j))
+				if !assert.NoError(t, store.SaveAccount(context.Background(), acc)) {
+					return
+				}
+				loggedIn, err := store.Login(context.Background(), acc.Code())
+				if assert.NoError(t, err) {
+					assert.Equal(t, acc.ID(), loggedIn.ID())
+				}
+				assert.NoError(t, store.RenameAccount(context.Background(), acc.ID(), "Renamed Student"))
+				_, err = store.GetAccounts(context.Background(), account.RoleStudent)
+				assert.NoError(t, err)
+				_, err = store.CountAccounts(context.Background())
+				assert.NoError(t, err)
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	n, err := store.CountAccounts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, workers*accounts, n)
+}
+
+func testConcurrentCodeClaims(t *testing.T, store account.Store) {
+	const workers = 8
+	var wg sync.WaitGroup
+	errs := make([]error, workers)
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			acc := account.NewStudent(fmt.Sprintf("Student %d", n))
+			if errs[n] = store.SaveAccount(context.Background(), acc); errs[n] != nil {
+				return
+			}
+			_, errs[n] = store.ChangeCode(context.Background(), acc.ID(), "SAME")
+		}(n)
+	}
+	wg.Wait()
+
+	claimed := 0
+	for _, err := range errs {
+		if err == nil {
+			claimed++
+			continue
+		}
+		assertConflict(t, err)
+	}
+	assert.Equal(t, 1, claimed, "only one account may have the code")
+}
@@ -0,0 +1,356 @@
+package storetest
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// everyOrder is each way a listing can be sorted.
+var everyOrder = []achievements.ListOptions{
+	{Sort: achievements.SortCreated},
+	{Sort: achievements.SortCreated, Descending: true},
+	{Sort: achievements.SortName},
+	{Sort: achievements.SortName, Descending: true},
+	{Sort: achievements.SortDueDate},
+	{Sort: achievements.SortDueDate, Descending: true},
+}
+
+func testListAchievements(t *testing.T, s achievements.Store) {
+	ids := make(map[string]string)
+	for _, name := range []string{"Bake Bread", "Adopt a Pet", "Climb a Hill", "Dig a Pond", "Clean the Bins"} {
+		id, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: name})
+		require.NoError(t, err)
+		ids[name] = id
+	}
+	march := time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2022, time.February, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, s.SetDueDate(context.Background(), ids["Climb a Hill"], &march))
+	require.NoError(t, s.SetDueDate(context.Background(), ids["Adopt a Pet"], &february))
+	require.NoError(t, s.ArchiveAchievement(context.Background(), ids["Clean the Bins"]))
+	active := achievements.Filter{Status: achievements.StatusActive}
+
+	t.Run("should list every active achievement in the order asked for", func(t *testing.T) {
+		for _, opts := range everyOrder {
+			opts.Filter = active
+			all, err := s.ListAchievements(context.Background(), opts)
+			require.NoError(t, err)
+			assert.Len(t, all, 4, opts)
+			assert.True(t, sort.SliceIsSorted(all, func(i, j int) bool {
+				return opts.Less(all[i], all[j])
+			}), opts)
+		}
+	})
+
+	t.Run("should list active achievements in the default order", func(t *testing.T) {
+		all, err := s.GetAllAchievements(context.Background())
+		require.NoError(t, err)
+		listed, err := s.ListAchievements(context.Background(), achievements.ListOptions{Filter: active, Sort: achievements.SortCreated})
+		require.NoError(t, err)
+		assert.Equal(t, listed, all)
+	})
+
+	t.Run("should sort by name", func(t *testing.T) {
+		all, err := s.ListAchievements(context.Background(), achievements.ListOptions{Filter: active, Sort: achievements.SortName})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Adopt a Pet", "Bake Bread", "Climb a Hill", "Dig a Pond"}, achievementNames(all))
+	})
+
+	t.Run("should sort by due date, with achievements without one last", func(t *testing.T) {
+		all, err := s.ListAchievements(context.Background(), achievements.ListOptions{Filter: active, Sort: achievements.SortDueDate})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Adopt a Pet", "Climb a Hill"}, achievementNames(all)[:2])
+		all, err = s.ListAchievements(context.Background(), achievements.ListOptions{Filter: active, Sort: achievements.SortDueDate, Descending: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Climb a Hill", "Adopt a Pet"}, achievementNames(all)[2:])
+	})
+
+	t.Run("should page through every achievement once", func(t *testing.T) {
+		for _, opts := range everyOrder {
+			opts.Filter = active
+			all, err := s.ListAchievements(context.Background(), opts)
+			require.NoError(t, err)
+			paged := pageThrough(t, func(after string) ([]string, error) {
+				opts := opts
+				opts.After, opts.Limit = after, 3
+				page, err := s.ListAchievements(context.Background(), opts)
+				return achievementIDs(page), err
+			})
+			assert.Equal(t, achievementIDs(all), paged, opts)
+		}
+	})
+
+	t.Run("should carry on after an achievement archived since its page was listed", func(t *testing.T) {
+		rest, err := s.ListAchievements(context.Background(), achievements.ListOptions{Filter: active, Sort: achievements.SortName, After: ids["Clean the Bins"]})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Climb a Hill", "Dig a Pond"}, achievementNames(rest))
+	})
+
+	t.Run("should not carry on after an achievement that does not exist", func(t *testing.T) {
+		_, err := s.ListAchievements(context.Background(), achievements.ListOptions{After: "non-existent-id"})
+		assert.ErrorIs(t, err, achievements.ErrUnknownCursor)
+	})
+}
+
+func testListStudentAchievements(t *testing.T, s achievements.Store) {
+	bake, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Bake Bread"})
+	require.NoError(t, err)
+	adopt, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Adopt a Pet"})
+	require.NoError(t, err)
+	for _, id := range []string{bake, adopt, "missing"} {
+		require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{
+			AchievementID: id,
+			StudentID:     "student",
+			Progress:      achievements.Started,
+		}, "teacher"))
+	}
+	other, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Climb a Hill"})
+	require.NoError(t, err)
+	require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{
+		AchievementID: other,
+		StudentID:     "other-student",
+		Progress:      achievements.Started,
+	}, "teacher"))
+
+	t.Run("should order progress by its achievement, counting a missing one as only an ID", func(t *testing.T) {
+		aa, err := s.ListStudentAchievements(context.Background(), "student", achievements.ListOptions{Sort: achievements.SortName})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"missing", adopt, bake}, progressIDs(aa))
+		aa, err = s.ListStudentAchievements(context.Background(), "student", achievements.ListOptions{Sort: achievements.SortName, Descending: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{bake, adopt, "missing"}, progressIDs(aa))
+	})
+
+	t.Run("should list a student's progress in the default order", func(t *testing.T) {
+		all, err := s.GetStudentAchievements(context.Background(), "student")
+		require.NoError(t, err)
+		listed, err := s.ListStudentAchievements(context.Background(), "student", achievements.ListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, listed, all)
+		assert.Equal(t, "missing", all[0].AchievementID)
+	})
+
+	t.Run("should page through every achievement once", func(t *testing.T) {
+		for _, opts := range everyOrder {
+			all, err := s.ListStudentAchievements(context.Background(), "student", opts)
+			require.NoError(t, err)
+			require.Len(t, all, 3)
+			paged := pageThrough(t, func(after string) ([]string, error) {
+				opts := opts
+				opts.After, opts.Limit = after, 2
+				page, err := s.ListStudentAchievements(context.Background(), "student", opts)
+				return progressIDs(page), err
+			})
+			assert.Equal(t, progressIDs(all), paged, opts)
+		}
+	})
+
+	t.Run("should carry on after an achievement the student is not on", func(t *testing.T) {
+		rest, err := s.ListStudentAchievements(context.Background(), "student", achievements.ListOptions{Sort: achievements.SortName, After: other})
+		require.NoError(t, err)
+		assert.Empty(t, rest)
+		rest, err = s.ListStudentAchievements(context.Background(), "student", achievements.ListOptions{Sort: achievements.SortName, Descending: true, After: other})
+		require.NoError(t, err)
+		assert.Equal(t, []string{bake, adopt, "missing"}, progressIDs(rest))
+	})
+
+	t.Run("should not carry on after an achievement that does not exist", func(t *testing.T) {
+		_, err := s.ListStudentAchievements(context.Background(), "student", achievements.ListOptions{After: "non-existent-id"})
+		assert.ErrorIs(t, err, achievements.ErrUnknownCursor)
+	})
+}
+
+func testFilterAchievements(t *testing.T, s achievements.Store) {
+	february := time.Date(2022, time.February, 1, 0, 0, 0, 0, time.UTC)
+	midFebruary := february.AddDate(0, 0, 14)
+	march := time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC)
+	ids := make(map[string]string)
+	for _, a := range []achievements.Achievement{
+		{Name: "Bake Bread", Category: "Cooking", DueDate: &february},
+		{Name: "Bake a Cake", Category: "Cooking"},
+		{Name: "Climb a Hill", Category: "Outdoors", DueDate: &march},
+		{Name: "Dig a Pond", Category: "Outdoors", DueDate: &midFebruary},
+	} {
+		id, err := s.CreateAchievement(context.Background(), a)
+		require.NoError(t, err)
+		ids[a.Name] = id
+	}
+	require.NoError(t, s.ArchiveAchievement(context.Background(), ids["Dig a Pond"]))
+
+	tests := []struct {
+		name   string
+		filter achievements.Filter
+		want   []string
+	}{
+		{"by name, ignoring case", achievements.Filter{Name: "BAKE"}, []string{"Bake Bread", "Bake a Cake"}},
+		{"by category", achievements.Filter{Category: "Outdoors"}, []string{"Climb a Hill", "Dig a Pond"}},
+		{"active only", achievements.Filter{Status: achievements.StatusActive}, []string{"Bake Bread", "Bake a Cake", "Climb a Hill"}},
+		{"archived only", achievements.Filter{Status: achievements.StatusArchived}, []string{"Dig a Pond"}},
+		{"due from a date", achievements.Filter{DueFrom: &midFebruary}, []string{"Climb a Hill", "Dig a Pond"}},
+		{"due before a date", achievements.Filter{DueBefore: &midFebruary}, []string{"Bake Bread"}},
+		{"due in a range", achievements.Filter{DueFrom: &february, DueBefore: &march}, []string{"Bake Bread", "Dig a Pond"}},
+		{"by everything at once", achievements.Filter{Name: "a", Category: "Outdoors", Status: achievements.StatusActive, DueFrom: &february}, []string{"Climb a Hill"}},
+		{"nothing", achievements.Filter{Name: "Swim"}, []string{}},
+	}
+	for _, test := range tests {
+		all, err := s.ListAchievements(context.Background(), achievements.ListOptions{Filter: test.filter, Sort: achievements.SortName})
+		require.NoError(t, err)
+		assert.Equal(t, test.want, achievementNames(all), test.name)
+	}
+
+	t.Run("should page through only the matching achievements", func(t *testing.T) {
+		paged := pageThrough(t, func(after string) ([]string, error) {
+			page, err := s.ListAchievements(context.Background(), achievements.ListOptions{
+				Filter: achievements.Filter{Category: "Cooking"},
+				Sort:   achievements.SortName,
+				After:  after,
+				Limit:  1,
+			})
+			return achievementIDs(page), err
+		})
+		assert.Equal(t, []string{ids["Bake Bread"], ids["Bake a Cake"]}, paged)
+	})
+	t.Run("should carry on after an achievement from a different filter", func(t *testing.T) {
+		rest, err := s.ListAchievements(context.Background(), achievements.ListOptions{
+			Filter: achievements.Filter{Category: "Outdoors"},
+			Sort:   achievements.SortName,
+			After:  ids["Bake a Cake"],
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Climb a Hill", "Dig a Pond"}, achievementNames(rest))
+	})
+}
+
+func testFilterStudentAchievements(t *testing.T, s achievements.Store) {
+	bake, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Bake Bread", Category: "Cooking"})
+	require.NoError(t, err)
+	climb, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Climb a Hill"})
+	require.NoError(t, err)
+	for id, progress := range map[string]achievements.Progress{
+		bake:      achievements.Started,
+		climb:     achievements.Finished,
+		"missing": achievements.NotStarted,
+	} {
+		require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{
+			AchievementID: id,
+			StudentID:     "student",
+			Progress:      progress,
+		}, "teacher"))
+	}
+	dig, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Dig a Pond"})
+	require.NoError(t, err)
+	require.NoError(t, s.AddProgression(context.Background(), achievements.StudentAchievement{
+		AchievementID: dig,
+		StudentID:     "other-student",
+		Progress:      achievements.Started,
+	}, "teacher"))
+	archived, err := s.CreateAchievement(context.Background(), achievements.Achievement{Name: "Empty the Bins"})
+	require.NoError(t, err)
+	require.NoError(t, s.ArchiveAchievement(context.Background(), archived))
+	notStarted := []achievements.Progress{achievements.NotStarted}
+
+	tests := []struct {
+		name   string
+		filter achievements.Filter
+		want   []string
+	}{
+		{"by progress", achievements.Filter{Progress: []achievements.Progress{achievements.Started}}, []string{bake}},
+		{"by any of several progresses", achievements.Filter{Progress: []achievements.Progress{achievements.NotStarted, achievements.Finished}}, []string{"missing", climb, dig}},
+		{"counting an active achievement without progress as not started", achievements.Filter{Progress: notStarted}, []string{"missing", dig}},
+		{"by name and not started", achievements.Filter{Name: "pond", Progress: notStarted}, []string{dig}},
+		{"by category", achievements.Filter{Category: "Cooking"}, []string{bake}},
+		{"by name and progress", achievements.Filter{Name: "hill", Progress: []achievements.Progress{achievements.Finished}}, []string{climb}},
+		{"counting a missing achievement as active", achievements.Filter{Status: achievements.StatusActive}, []string{"missing", bake, climb}},
+		{"nothing", achievements.Filter{Progress: []achievements.Progress{achievements.Submitted}}, []string{}},
+	}
+	for _, test := range tests {
+		aa, err := s.ListStudentAchievements(context.Background(), "student", achievements.ListOptions{Filter: test.filter, Sort: achievements.SortName})
+		require.NoError(t, err)
+		assert.Equal(t, test.want, progressIDs(aa), test.name)
+	}
+
+	t.Run("should list an achievement without progress as not started", func(t *testing.T) {
+		aa, err := s.ListStudentAchievements(context.Background(), "student", achievements.ListOptions{Filter: achievements.Filter{Progress: notStarted}, Sort: achievements.SortName})
+		require.NoError(t, err)
+		require.Len(t, aa, 2)
+		assert.Equal(t, achievements.StudentAchievement{StudentID: "student", AchievementID: dig, Progress: achievements.NotStarted}, aa[1])
+	})
+
+	t.Run("should page through achievements without progress", func(t *testing.T) {
+		for _, opts := range everyOrder {
+			opts.Filter.Progress = []achievements.Progress{achievements.NotStarted, achievements.Started}
+			all, err := s.ListStudentAchievements(context.Background(), "student", opts)
+			require.NoError(t, err)
+			require.Len(t, all, 3)
+			paged := pageThrough(t, func(after string) ([]string, error) {
+				opts := opts
+				opts.After, opts.Limit = after, 1
+				page, err := s.ListStudentAchievements(context.Background(), "student", opts)
+				return progressIDs(page), err
+			})
+			assert.Equal(t, progressIDs(all), paged, opts)
+		}
+	})
+	t.Run("should carry on after an achievement from a different filter", func(t *testing.T) {
+		finished := achievements.Filter{Progress: []achievements.Progress{achievements.Finished}}
+		for cursor, want := range map[string][]string{
+			bake:     {climb},
+			dig:      {},
+			archived: {},
+		} {
+			rest, err := s.ListStudentAchievements(context.Background(), "student", achievements.ListOptions{Filter: finished, Sort: achievements.SortName, After: cursor})
+			require.NoError(t, err)
+			assert.Equal(t, want, progressIDs(rest), cursor)
+		}
+	})
+}
+
+// pageThrough calls list with the last ID of each page until a page
+// comes back empty, returning the IDs of them all. It gives up after
+// maxPages, in case a store keeps returning the same page.
+func pageThrough(t *testing.T, list func(after string) ([]string, error)) []string {
+	t.Helper()
+	const maxPages = 10
+	var all []string
+	after := ""
+	for i := 0; i < maxPages; i++ {
+		page, err := list(after)
+		require.NoError(t, err)
+		if len(page) == 0 {
+			return all
+		}
+		all = append(all, page...)
+		after = page[len(page)-1]
+	}
+	t.Fatalf("listing did not end after %d pages", maxPages)
+	return nil
+}
+
+func achievementIDs(aa []achievements.Achievement) []string {
+	ids := make([]string, len(aa))
+	for i, a := range aa {
+		ids[i] = a.ID
+	}
+	return ids
+}
+
+func achievementNames(aa []achievements.Achievement) []string {
+	names := make([]string, len(aa))
+	for i, a := range aa {
+		names[i] = a.Name
+	}
+	return names
+}
+
+func progressIDs(aa []achievements.StudentAchievement) []string {
+	ids := make([]string, len(aa))
+	for i, a := range aa {
+		ids[i] = a.AchievementID
+	}
+	return ids
+}
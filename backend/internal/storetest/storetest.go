@@ -0,0 +1,101 @@
+// Package storetest checks that an implementation of achievements.Store
+// or account.Store keeps the contract the rest of medlock relies on:
+// what each method returns, the order listings and history come back
+// in, which errors mark something as not found or conflicting, how
+// saving over existing progress behaves, and that concurrent use is
+// safe.
+//
+// A backend runs the whole suite from its own tests:
+//
+//	func TestStore(t *testing.T) {
+//		storetest.TestAchievementStore(t, func(t *testing.T) achievements.Store {
+//			return newStore(t)
+//		})
+//	}
+//
+// The checks are most thorough run with the race detector.
+package storetest
+
+import (
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+)
+
+// TestAchievementStore runs every achievements.Store check as a subtest
+// of t. newStore is called for each of them, possibly from parallel
+// tests, and must return a new, empty store.
+func TestAchievementStore(t *testing.T, newStore func(t *testing.T) achievements.Store) {
+	for _, test := range achievementStoreTests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			test.run(t, newStore(t))
+		})
+	}
+}
+
+// TestAccountStore runs every account.Store check as a subtest of t.
+// newStore is called for each of them, possibly from parallel tests, and
+// must return a new, empty store.
+func TestAccountStore(t *testing.T, newStore func(t *testing.T) account.Store) {
+	for _, test := range accountStoreTests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			test.run(t, newStore(t))
+		})
+	}
+}
+
+var achievementStoreTests = []struct {
+	name string
+	run  func(t *testing.T, s achievements.Store)
+}{
+	{"GetStudentAchievements", testGetStudentAchievements},
+	{"GetAchievementsForStudents", testGetAchievementsForStudents},
+	{"GetStudentsByAchievement", testGetStudentsByAchievement},
+	{"AddProgressionOverwrites", testAddProgressionOverwrites},
+	{"AchievementExists", testAchievementExists},
+	{"GetAchievement", testGetAchievement},
+	{"GetAllAchievements", testGetAllAchievements},
+	{"SetDueDate", testSetDueDate},
+	{"GetOverdueAchievements", testGetOverdueAchievements},
+	{"Points", testPoints},
+	{"Rewards", testRewards},
+	{"UpdateAchievement", testUpdateAchievement},
+	{"ArchiveAchievement", testArchiveAchievement},
+	{"ListAchievements", testListAchievements},
+	{"ListStudentAchievements", testListStudentAchievements},
+	{"FilterAchievements", testFilterAchievements},
+	{"FilterStudentAchievements", testFilterStudentAchievements},
+	{"GetProgressHistory", testGetProgressHistory},
+	{"ReviewSubmission", testReviewSubmission},
+	{"GetSubmissions", testGetSubmissions},
+	{"ErrorKinds", testErrorKinds},
+	{"CancelledContext", testCancelledContext},
+	{"ConcurrentProgressions", testConcurrentProgressions},
+	{"ConcurrentRedemptions", testConcurrentRedemptions},
+}
+
+var accountStoreTests = []struct {
+	name string
+	run  func(t *testing.T, store account.Store)
+}{
+	{"SaveAccountConflict", testSaveAccountConflict},
+	{"SaveAccounts", testSaveAccounts},
+	{"Login", testLogin},
+	{"AccountExists", testAccountExists},
+	{"GetAccount", testGetAccount},
+	{"GetAccounts", testGetAccounts},
+	{"RenameAccount", testRenameAccount},
+	{"DeleteAccount", testDeleteAccount},
+	{"ChangeCode", testChangeCode},
+	{"CountAccounts", testCountAccounts},
+	{"PasswordHash", testPasswordHash},
+	{"AccountErrorKinds", testAccountErrorKinds},
+	{"AccountCancelledContext", testAccountCancelledContext},
+	{"ConcurrentSavesAndLogins", testConcurrentSavesAndLogins},
+	{"ConcurrentCodeClaims", testConcurrentCodeClaims},
+}
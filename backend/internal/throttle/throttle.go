@@ -0,0 +1,228 @@
+// Package throttle slows down repeated failed logins with a code, such
+// as guessing a teacher's password or retrying a mistyped code.
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// Policy sets how failed attempts are limited. Failures are counted
+// for each client and code together, so pupils sharing a school's
+// address are not locked out by one another's typos.
+type Policy struct {
+	// Free is how many failures a client may make with a code before
+	// it has to back off.
+	Free int
+	// Backoff is how long a client is locked out of a code after its
+	// first failure beyond Free. It doubles with each further failure,
+	// up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Forget is how long a client has to go without failing with a code
+	// for its failures to be forgotten.
+	Forget time.Duration
+	// History is how many of the latest failed attempts are kept for
+	// review.
+	History int
+}
+
+// DefaultPolicy allows a few typos, then backs off from a second up to
+// a quarter of an hour.
+var DefaultPolicy = Policy{
+	Free:       5,
+	Backoff:    time.Second,
+	MaxBackoff: 15 * time.Minute,
+	Forget:     time.Hour,
+	History:    500,
+}
+
+// Attempt is a failed login attempt, either with a code that does not
+// exist or with the wrong password for AccountID. The code is left out
+// of the latter, since it is a working one.
+type Attempt struct {
+	Client    string
+	Code      string
+	AccountID string
+	Time      time.Time
+}
+
+type client struct {
+	failures    int
+	pending     int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// key is what failures are counted by: a client, such as the remote
+// address, and the code it tried.
+type key struct {
+	client string
+	code   string
+}
+
+// Guard tracks failed attempts by client and code, and decides when a
+// client has to wait before trying a code again. It is safe for
+// concurrent use.
+type Guard struct {
+	policy Policy
+	now    func() time.Time
+
+	mu        sync.Mutex
+	clients   map[key]*client
+	attempts  []Attempt
+	lastSweep time.Time
+}
+
+func NewGuard(policy Policy) *Guard {
+	return &Guard{
+		policy:  policy,
+		now:     time.Now,
+		clients: make(map[key]*client),
+	}
+}
+
+// Pending is a login attempt let in by Begin that has not been settled
+// yet. It is counted as though it will fail, so that attempts made at
+// the same time cannot all get in under the limits.
+type Pending struct {
+	guard   *Guard
+	key     key
+	settled bool
+}
+
+// Begin reports whether client may attempt to log in with code now
+// and, if not, how long it has to wait. An attempt that is let in has
+// to be settled with Fail, FailPassword, Succeed or Done.
+func (g *Guard) Begin(clientID string, code string) (*Pending, time.Duration, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.now()
+	g.sweep(now)
+
+	k := key{client: clientID, code: code}
+	c, ok := g.clients[k]
+	if !ok {
+		c = &client{}
+		g.clients[k] = c
+	}
+	if now.Sub(c.lastFailure) >= g.policy.Forget {
+		c.failures = 0
+	}
+	wait := time.Duration(0)
+	switch {
+	case now.Before(c.lockedUntil):
+		wait = c.lockedUntil.Sub(now)
+	case c.pending > 0 && c.failures+c.pending >= g.policy.Free:
+		// Past the free failures a client gets one attempt at a time.
+		wait = g.policy.Backoff
+	}
+	if wait > 0 {
+		if c.failures == 0 && c.pending == 0 {
+			delete(g.clients, k)
+		}
+		return nil, wait, false
+	}
+	c.pending++
+	return &Pending{guard: g, key: k}, 0, true
+}
+
+// Fail settles the attempt as failed because its code does not exist,
+// locking the client out of the code if it has failed too often.
+func (p *Pending) Fail() {
+	p.guard.settle(p, &Attempt{Client: p.key.client, Code: p.key.code}, false)
+}
+
+// FailPassword settles the attempt as failed with the wrong password
+// for the account with accountID, counting towards the same limits as
+// Fail.
+func (p *Pending) FailPassword(accountID string) {
+	p.guard.settle(p, &Attempt{Client: p.key.client, AccountID: accountID}, false)
+}
+
+// Succeed settles the attempt as having logged in, which clears the
+// client's earlier failures with the code.
+func (p *Pending) Succeed() {
+	p.guard.settle(p, nil, true)
+}
+
+// Done settles the attempt as neither failing nor succeeding, such as
+// when it could not be checked, so earlier failures still count. Done
+// does nothing once the attempt has been settled, so it can be
+// deferred.
+func (p *Pending) Done() {
+	p.guard.settle(p, nil, false)
+}
+
+func (g *Guard) settle(p *Pending, failed *Attempt, succeeded bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if p.settled {
+		return
+	}
+	p.settled = true
+	c := g.clients[p.key]
+	c.pending--
+	if failed == nil {
+		if succeeded {
+			c.failures = 0
+			c.lockedUntil = time.Time{}
+		}
+		if c.failures == 0 && c.pending == 0 {
+			delete(g.clients, p.key)
+		}
+		return
+	}
+
+	now := g.now()
+	c.failures++
+	c.lastFailure = now
+	if over := c.failures - g.policy.Free; over > 0 {
+		c.lockedUntil = now.Add(g.backoff(over))
+	}
+
+	if g.policy.History > 0 {
+		if len(g.attempts) >= g.policy.History {
+			g.attempts = g.attempts[1:]
+		}
+		failed.Time = now
+		g.attempts = append(g.attempts, *failed)
+	}
+}
+
+// Attempts returns the latest failed attempts, newest first.
+func (g *Guard) Attempts() []Attempt {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	attempts := make([]Attempt, len(g.attempts))
+	for i, a := range g.attempts {
+		attempts[len(attempts)-1-i] = a
+	}
+	return attempts
+}
+
+// backoff is the lockout for the nth failure beyond the free ones.
+func (g *Guard) backoff(n int) time.Duration {
+	d := g.policy.Backoff
+	for i := 1; i < n && d < g.policy.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > g.policy.MaxBackoff {
+		d = g.policy.MaxBackoff
+	}
+	return d
+}
+
+// sweep forgets clients that have not failed for a while, so clients
+// that never come back do not accumulate.
+func (g *Guard) sweep(now time.Time) {
+	if now.Sub(g.lastSweep) < g.policy.Forget {
+		return
+	}
+	g.lastSweep = now
+	for k, c := range g.clients {
+		if c.pending == 0 && now.Sub(c.lastFailure) >= g.policy.Forget && !now.Before(c.lockedUntil) {
+			delete(g.clients, k)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package throttle
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newTestGuard(policy Policy) (*Guard, *clock) {
+	c := &clock{now: time.Date(2021, 11, 1, 9, 0, 0, 0, time.UTC)}
+	g := NewGuard(policy)
+	g.now = c.Now
+	return g, c
+}
+
+var testPolicy = Policy{
+	Free:       2,
+	Backoff:    time.Second,
+	MaxBackoff: 8 * time.Second,
+	Forget:     time.Hour,
+	History:    3,
+}
+
+// fail makes a failed attempt by client with code, which has to be let
+// in.
+func fail(t *testing.T, g *Guard, clientID string, code string) {
+	t.Helper()
+	attempt, _, ok := g.Begin(clientID, code)
+	require.True(t, ok, "the attempt should be let in")
+	attempt.Fail()
+}
+
+// allow reports whether client may attempt to log in with code now,
+// settling the attempt as neither failed nor succeeded if so.
+func allow(g *Guard, clientID string, code string) (time.Duration, bool) {
+	attempt, wait, ok := g.Begin(clientID, code)
+	if ok {
+		attempt.Done()
+	}
+	return wait, ok
+}
+
+func TestGuardBackoff(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should allow the free failures", func(t *testing.T) {
+		g, _ := newTestGuard(testPolicy)
+		fail(t, g, "client", "AAAA")
+		fail(t, g, "client", "AAAB")
+		_, ok := allow(g, "client", "AAAA")
+		assert.True(t, ok)
+	})
+
+	t.Run("should double the lockout with each failure up to the maximum", func(t *testing.T) {
+		g, c := newTestGuard(testPolicy)
+		fail(t, g, "client", "AAAA")
+		fail(t, g, "client", "AAAA")
+		for _, want := range []time.Duration{1, 2, 4, 8, 8} {
+			fail(t, g, "client", "AAAA")
+			wait, ok := allow(g, "client", "AAAA")
+			assert.False(t, ok)
+			assert.Equal(t, want*time.Second, wait)
+			c.now = c.now.Add(wait)
+		}
+	})
+
+	t.Run("should allow the client again once the lockout has passed", func(t *testing.T) {
+		g, c := newTestGuard(testPolicy)
+		for i := 0; i < 3; i++ {
+			fail(t, g, "client", "AAAA")
+		}
+		c.now = c.now.Add(time.Second)
+		_, ok := allow(g, "client", "AAAA")
+		assert.True(t, ok)
+	})
+
+	t.Run("should only lock out the failing client", func(t *testing.T) {
+		g, _ := newTestGuard(testPolicy)
+		for i := 0; i < 3; i++ {
+			fail(t, g, "client", "AAAA")
+		}
+		_, ok := allow(g, "someone else", "AAAA")
+		assert.True(t, ok)
+	})
+
+	t.Run("should count wrong passwords as failures", func(t *testing.T) {
+		g, _ := newTestGuard(testPolicy)
+		fail(t, g, "client", "AAAA")
+		for i := 0; i < 2; i++ {
+			attempt, _, ok := g.Begin("client", "AAAA")
+			require.True(t, ok)
+			attempt.FailPassword("teacher")
+		}
+		_, ok := allow(g, "client", "AAAA")
+		assert.False(t, ok)
+	})
+
+	t.Run("should only lock the client out of the failing code", func(t *testing.T) {
+		g, _ := newTestGuard(testPolicy)
+		for i := 0; i < 3; i++ {
+			fail(t, g, "client", "AAAA")
+		}
+		_, ok := allow(g, "client", "BBBB")
+		assert.True(t, ok, "others sharing the address should still be able to log in")
+	})
+
+	t.Run("should forget failures after a successful login", func(t *testing.T) {
+		g, _ := newTestGuard(testPolicy)
+		fail(t, g, "client", "AAAA")
+		fail(t, g, "client", "AAAA")
+		attempt, _, ok := g.Begin("client", "AAAA")
+		require.True(t, ok)
+		attempt.Succeed()
+		fail(t, g, "client", "AAAA")
+		fail(t, g, "client", "AAAA")
+		_, ok = allow(g, "client", "AAAA")
+		assert.True(t, ok)
+	})
+
+	t.Run("should keep failures after an attempt that was not checked", func(t *testing.T) {
+		g, _ := newTestGuard(testPolicy)
+		fail(t, g, "client", "AAAA")
+		fail(t, g, "client", "AAAA")
+		_, ok := allow(g, "client", "AAAA")
+		require.True(t, ok)
+		fail(t, g, "client", "AAAA")
+		_, ok = allow(g, "client", "AAAA")
+		assert.False(t, ok)
+	})
+
+	t.Run("should forget failures after a quiet spell", func(t *testing.T) {
+		g, c := newTestGuard(testPolicy)
+		fail(t, g, "client", "AAAA")
+		fail(t, g, "client", "AAAA")
+		c.now = c.now.Add(time.Hour)
+		fail(t, g, "client", "AAAA")
+		_, ok := allow(g, "client", "AAAA")
+		assert.True(t, ok)
+	})
+
+	t.Run("should count attempts in progress as failures", func(t *testing.T) {
+		g, _ := newTestGuard(testPolicy)
+		first, _, ok := g.Begin("client", "AAAA")
+		require.True(t, ok)
+		second, _, ok := g.Begin("client", "AAAA")
+		require.True(t, ok)
+		_, ok = allow(g, "client", "AAAA")
+		assert.False(t, ok, "only the free failures should be in progress at once")
+
+		first.Done()
+		second.Done()
+		second.Fail()
+		_, ok = allow(g, "client", "AAAA")
+		assert.True(t, ok, "settling twice should not count")
+	})
+
+	t.Run("should let one attempt at a time in past the free failures", func(t *testing.T) {
+		g, _ := newTestGuard(testPolicy)
+		fail(t, g, "client", "AAAA")
+		fail(t, g, "client", "AAAA")
+		attempt, _, ok := g.Begin("client", "AAAA")
+		require.True(t, ok)
+		wait, ok := allow(g, "client", "AAAA")
+		assert.False(t, ok)
+		assert.Equal(t, testPolicy.Backoff, wait)
+		attempt.Fail()
+		wait, ok = allow(g, "client", "AAAA")
+		assert.False(t, ok)
+		assert.Equal(t, time.Second, wait)
+	})
+}
+
+func TestGuardAttempts(t *testing.T) {
+	t.Parallel()
+	g, c := newTestGuard(testPolicy)
+	for i, code := range []string{"AAAA", "BBBB", "CCCC"} {
+		c.now = c.now.Add(time.Second)
+		fail(t, g, fmt.Sprintf("client %d", i), code)
+	}
+	c.now = c.now.Add(time.Second)
+	attempt, _, ok := g.Begin("client 3", "DDDD")
+	require.True(t, ok)
+	attempt.FailPassword("teacher")
+
+	attempts := g.Attempts()
+	if assert.Len(t, attempts, 3) {
+		assert.Equal(t, Attempt{Client: "client 3", AccountID: "teacher", Time: c.now}, attempts[0])
+		assert.Equal(t, "CCCC", attempts[1].Code)
+		assert.Equal(t, "BBBB", attempts[2].Code)
+	}
+}
+
+func TestGuardConcurrentUse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should be safe to use from many goroutines", func(t *testing.T) {
+		g := NewGuard(Policy{Forget: time.Hour, History: 500})
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				id := fmt.Sprintf("client %d", i)
+				for j := 0; j < 100; j++ {
+					if attempt, _, ok := g.Begin(id, "AAAA"); ok {
+						attempt.Fail()
+					}
+					g.Attempts()
+				}
+			}(i)
+		}
+		wg.Wait()
+		assert.Len(t, g.Attempts(), 500)
+	})
+
+	t.Run("should not let simultaneous attempts past the free failures", func(t *testing.T) {
+		g, _ := newTestGuard(testPolicy)
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		let := 0
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, _, ok := g.Begin("client", "AAAA"); ok {
+					mu.Lock()
+					let++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, testPolicy.Free, let)
+	})
+}
@@ -0,0 +1,260 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/evidence"
+	"github.com/Manchester-Dev/medlock/internal/session"
+	"github.com/go-chi/chi/v5"
+)
+
+type accountRequest struct {
+	Name string `json:"name"`
+}
+
+// accountResponse includes the login code so teachers can hand it out.
+type accountResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Code string `json:"code"`
+}
+
+func toAccountResponse(acc account.Account) accountResponse {
+	return accountResponse{
+		ID:   acc.ID(),
+		Name: acc.Name(),
+		Type: toType(acc.Role()),
+		Code: acc.Code(),
+	}
+}
+
+func createAccount(accountStore account.Store, codes *account.CodeGenerator, role string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var accReq accountRequest
+		err := json.NewDecoder(req.Body).Decode(&accReq)
+		if err != nil {
+			writeError(w, errInvalidJSON)
+			return
+		}
+		name := strings.TrimSpace(accReq.Name)
+		if name == "" {
+			writeError(w, invalidField("name", "is required"))
+			return
+		}
+		acc, err := codes.CreateAccount(req.Context(), accountStore, name, role)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		err = json.NewEncoder(w).Encode(toAccountResponse(acc))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+type accountsResponse struct {
+	Students []accountResponse `json:"students"`
+}
+
+func getStudents(accountStore account.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		students, err := accountStore.GetAccounts(req.Context(), account.RoleStudent)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		sort.Slice(students, func(i, j int) bool {
+			return students[i].Name() < students[j].Name()
+		})
+		resp := accountsResponse{Students: make([]accountResponse, len(students))}
+		for i, s := range students {
+			resp.Students[i] = toAccountResponse(s)
+		}
+		err = json.NewEncoder(w).Encode(resp)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+// accountWithRole returns the account with the id in the URL, or nil
+// when there is none with the role.
+func accountWithRole(accountStore account.Store, req *http.Request, role string) account.Account {
+	acc, err := accountStore.GetAccount(req.Context(), chi.URLParam(req, "id"))
+	if err != nil || acc.Role() != role {
+		return nil
+	}
+	return acc
+}
+
+func renameAccount(accountStore account.Store, role string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		acc := accountWithRole(accountStore, req, role)
+		if acc == nil {
+			writeError(w, accountNotFound(role))
+			return
+		}
+		var accReq accountRequest
+		err := json.NewDecoder(req.Body).Decode(&accReq)
+		if err != nil {
+			writeError(w, errInvalidJSON)
+			return
+		}
+		name := strings.TrimSpace(accReq.Name)
+		if name == "" {
+			writeError(w, invalidField("name", "is required"))
+			return
+		}
+		err = accountStore.RenameAccount(req.Context(), acc.ID(), name)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+// regenerateCode replaces the login code of an account, such as when
+// it has been shared. The old code and any sessions started with it
+// stop working. As the new code is returned, teachers can do this for
+// any student but only for themselves among teachers.
+func regenerateCode(accountStore account.Store, codes *account.CodeGenerator, role string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		acc := accountWithRole(accountStore, req, role)
+		if acc == nil {
+			writeError(w, accountNotFound(role))
+			return
+		}
+		acc, err := codes.RegenerateCode(req.Context(), accountStore, acc.ID())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		err = json.NewEncoder(w).Encode(toAccountResponse(acc))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+type passwordRequest struct {
+	Current  string `json:"current"`
+	Password string `json:"password"`
+}
+
+// setPassword sets a teacher's own password, which they need alongside
+// their code to log in from then on. Changing an existing password needs
+// the current one. Other sessions are logged out, so a new token for
+// this one is returned.
+func setPassword(accountStore account.Store, sessions *session.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		acc := accountFromContext(req.Context())
+		var passwordReq passwordRequest
+		err := json.NewDecoder(req.Body).Decode(&passwordReq)
+		if err != nil {
+			writeError(w, errInvalidJSON)
+			return
+		}
+		ok, err := passwordMatches(req.Context(), accountStore, acc.ID(), passwordReq.Current)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if !ok {
+			writeError(w, errWrongPassword)
+			return
+		}
+		err = account.SetPassword(req.Context(), accountStore, acc.ID(), passwordReq.Password)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeSession(req.Context(), w, accountStore, sessions, acc)
+	}
+}
+
+// removePassword lets a teacher log in with their code alone again. Only
+// they can, giving their current password, so nobody else can strip it
+// from their login. Other sessions are logged out, so a new token for
+// this one is returned.
+func removePassword(accountStore account.Store, sessions *session.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		acc := accountFromContext(req.Context())
+		var passwordReq passwordRequest
+		err := json.NewDecoder(req.Body).Decode(&passwordReq)
+		if err != nil {
+			writeError(w, errInvalidJSON)
+			return
+		}
+		ok, err := passwordMatches(req.Context(), accountStore, acc.ID(), passwordReq.Current)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if !ok {
+			writeError(w, errWrongPassword)
+			return
+		}
+		err = account.RemovePassword(req.Context(), accountStore, acc.ID())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeSession(req.Context(), w, accountStore, sessions, acc)
+	}
+}
+
+// deleteAccount removes an account so its login code and sessions stop
+// working, along with any evidence uploaded for a student. Teachers
+// cannot delete themselves, so there is always someone left to manage
+// accounts.
+func deleteAccount(accountStore account.Store, locker *evidence.Locker, role string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		acc := accountWithRole(accountStore, req, role)
+		if acc == nil {
+			writeError(w, accountNotFound(role))
+			return
+		}
+		if acc.ID() == accountFromContext(req.Context()).ID() {
+			writeError(w, errDeleteSelf)
+			return
+		}
+		err := accountStore.DeleteAccount(req.Context(), acc.ID())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if role == account.RoleStudent {
+			err = locker.RemoveStudent(req.Context(), acc.ID())
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+		}
+	}
+}
+
+// getCodeUsage reports how full the space of login codes is, so
+// teachers know when codes should be made longer.
+func getCodeUsage(accountStore account.Store, codes *account.CodeGenerator) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		usage, err := codes.Usage(req.Context(), accountStore)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		err = json.NewEncoder(w).Encode(usage)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
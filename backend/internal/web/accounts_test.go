@@ -0,0 +1,279 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/evidence"
+	"github.com/Manchester-Dev/medlock/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountManagement(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	achievementStore := store.NewInMemory()
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	existing := givenAccount(t, accountStore, account.NewStudent("Existing Student"))
+	sessions := newTestSessions()
+	locker := newTestLocker()
+	r := NewRouter(accountStore, achievementStore, classroom.NewInMemoryStore(), locker, newTestCodes(t), newTestGuard(), sessions)
+
+	t.Run("should return forbidden for students", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, existing, http.MethodPost, "/students", `{"name": "New Student"}`)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		rr = doRequest(t, r, sessions, existing, http.MethodGet, "/students", "")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		rr = doRequest(t, r, sessions, existing, http.MethodPost, "/teachers", `{"name": "New Teacher"}`)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		rr = doRequest(t, r, sessions, existing, http.MethodPatch, "/students/"+existing.ID(), `{"name": "Renamed"}`)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		rr = doRequest(t, r, sessions, existing, http.MethodDelete, "/students/"+existing.ID(), "")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should return bad request for accounts without a name", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, "/students", `{"name": " "}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodPatch, "/students/"+existing.ID(), `{"name": ""}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	var created accountResponse
+	t.Run("should create a student who can log in with the returned code", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, "/students", `{"name": "New Student"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&created))
+		assert.Equal(t, "New Student", created.Name)
+		assert.Equal(t, "Student", created.Type)
+		require.NotEmpty(t, created.Code)
+
+		loggedIn := loginWithCode(t, r, created.Code)
+		assert.Equal(t, created.ID, loggedIn.ID)
+	})
+
+	t.Run("should create a teacher", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, "/teachers", `{"name": "New Teacher"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp accountResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		assert.Equal(t, "Teacher", resp.Type)
+		assert.Equal(t, "Teacher", loginWithCode(t, r, resp.Code).Type)
+	})
+
+	t.Run("should list students by name", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodGet, "/students", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp accountsResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		assert.Equal(t, []accountResponse{
+			{ID: existing.ID(), Name: "Existing Student", Type: "Student", Code: existing.Code()},
+			created,
+		}, resp.Students)
+	})
+
+	t.Run("should rename a student", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPatch, "/students/"+created.ID, `{"name": "Renamed Student"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		acc, err := accountStore.GetAccount(context.Background(), created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Renamed Student", acc.Name())
+	})
+
+	t.Run("should return not found for unknown accounts or the wrong role", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPatch, "/students/not-an-account", `{"name": "Renamed"}`)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodDelete, "/students/"+teacher.ID(), "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodPatch, "/teachers/"+existing.ID(), `{"name": "Renamed"}`)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should regenerate a login code, revoking the old one", func(t *testing.T) {
+		oldSession := loginWithCode(t, r, existing.Code())
+
+		rr := doRequest(t, r, sessions, existing, http.MethodPost, "/students/"+existing.ID()+"/code", "")
+		require.Equal(t, http.StatusForbidden, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodPost, "/students/"+existing.ID()+"/code", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp accountResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		assert.Equal(t, existing.ID(), resp.ID)
+		assert.NotEqual(t, existing.Code(), resp.Code)
+
+		assert.Equal(t, http.StatusUnauthorized, doLogin(t, r, existing.Code()).Code)
+		assert.Equal(t, existing.ID(), loginWithCode(t, r, resp.Code).ID)
+
+		req, err := http.NewRequest(http.MethodGet, "/students/"+existing.ID()+"/achievements", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+oldSession.Token)
+		rr = httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code, "sessions started with the old code should end")
+
+		rr = doRequest(t, r, sessions, teacher, http.MethodPost, "/students/not-an-account/code", "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should not let a teacher delete themselves", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodDelete, "/teachers/"+teacher.ID(), "")
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should delete a student along with their evidence", func(t *testing.T) {
+		achievementID := givenAchievement(t, achievementStore)
+		e, err := locker.Upload(context.Background(), evidence.Evidence{StudentID: created.ID, AchievementID: achievementID}, bytes.NewReader(testPhoto))
+		require.NoError(t, err)
+
+		rr := doRequest(t, r, sessions, teacher, http.MethodDelete, "/students/"+created.ID, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		exists, err := accountStore.AccountExists(context.Background(), created.ID)
+		require.NoError(t, err)
+		assert.False(t, exists)
+		_, _, err = locker.Open(context.Background(), e.ID)
+		assert.ErrorIs(t, err, evidence.ErrNotFound)
+
+		rr = doLogin(t, r, created.Code)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+func TestTeacherPasswords(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	colleague := givenAccount(t, accountStore, account.NewTeacher("Other Teacher"))
+	student := givenAccount(t, accountStore, account.NewStudent("Test Student"))
+	sessions := newTestSessions()
+	guard := newTestGuard()
+	r := NewRouter(accountStore, store.NewInMemory(), classroom.NewInMemoryStore(), newTestLocker(), newTestCodes(t), guard, sessions)
+	passwordURL := "/teachers/" + teacher.ID() + "/password"
+
+	withToken := func(t *testing.T, token, method, url, body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+	var token string
+
+	t.Run("should let a teacher set their password", func(t *testing.T) {
+		oldSession := loginWithCode(t, r, teacher.Code())
+		rr := doRequest(t, r, sessions, teacher, http.MethodPut, passwordURL, `{"password": "correct horse"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp loginResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		token = resp.Token
+		assert.Equal(t, http.StatusOK, withToken(t, token, http.MethodGet, "/achievements", "").Code)
+		assert.Equal(t, http.StatusUnauthorized, withToken(t, oldSession.Token, http.MethodGet, "/achievements", "").Code, "other sessions should end")
+	})
+
+	t.Run("should need the password to log in", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, doLogin(t, r, teacher.Code()).Code)
+		assert.Equal(t, http.StatusUnauthorized, doLoginWithPassword(t, r, teacher.Code(), "battery staple").Code)
+		rr := doLoginWithPassword(t, r, teacher.Code(), "correct horse")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp loginResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		assert.Equal(t, teacher.ID(), resp.ID)
+	})
+
+	t.Run("should record wrong passwords without the code", func(t *testing.T) {
+		attempts := guard.Attempts()
+		require.NotEmpty(t, attempts)
+		assert.Equal(t, teacher.ID(), attempts[0].AccountID)
+		assert.Empty(t, attempts[0].Code)
+	})
+
+	t.Run("should keep code only logins for students and other teachers", func(t *testing.T) {
+		loginWithCode(t, r, student.Code())
+		loginWithCode(t, r, colleague.Code())
+	})
+
+	t.Run("should need the current password to change it", func(t *testing.T) {
+		rr := withToken(t, token, http.MethodPut, passwordURL, `{"password": "battery staple"}`)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		rr = withToken(t, token, http.MethodPut, passwordURL, `{"current": "correct horse", "password": "short"}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		rr = withToken(t, token, http.MethodPut, passwordURL, `{"current": "correct horse", "password": "battery staple"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp loginResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		token = resp.Token
+		assert.Equal(t, http.StatusOK, doLoginWithPassword(t, r, teacher.Code(), "battery staple").Code)
+	})
+
+	t.Run("should not let anyone else set a teacher's password", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, colleague, http.MethodPut, passwordURL, `{"password": "mine now!"}`)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		rr = doRequest(t, r, sessions, student, http.MethodPut, "/teachers/"+student.ID()+"/password", `{"password": "mine now!"}`)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should not let a colleague take over a teacher's login", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, colleague, http.MethodDelete, passwordURL, `{}`)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		rr = doRequest(t, r, sessions, colleague, http.MethodPost, "/teachers/"+teacher.ID()+"/code", "")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.NotContains(t, rr.Body.String(), teacher.Code())
+		assert.Equal(t, http.StatusUnauthorized, doLogin(t, r, teacher.Code()).Code, "the password should still be needed")
+	})
+
+	t.Run("should need the current password to remove it", func(t *testing.T) {
+		rr := withToken(t, token, http.MethodDelete, passwordURL, `{"current": "correct horse"}`)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		rr = withToken(t, token, http.MethodDelete, passwordURL, `{"current": "battery staple"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp loginResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		token = resp.Token
+		assert.Equal(t, http.StatusOK, withToken(t, token, http.MethodGet, "/achievements", "").Code)
+		loginWithCode(t, r, teacher.Code())
+	})
+
+	t.Run("should let a teacher regenerate only their own code", func(t *testing.T) {
+		rr := withToken(t, token, http.MethodPost, "/teachers/"+teacher.ID()+"/code", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp accountResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		assert.NotEqual(t, teacher.Code(), resp.Code)
+		loginWithCode(t, r, resp.Code)
+	})
+}
+
+// doLogin sends an unauthenticated login request with code.
+func doLogin(t *testing.T, r http.Handler, code string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(fmt.Sprintf(`{"code": %q}`, code)))
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+// doLoginWithPassword sends an unauthenticated login request with code
+// and password.
+func doLoginWithPassword(t *testing.T, r http.Handler, code, password string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(fmt.Sprintf(`{"code": %q, "password": %q}`, code, password)))
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+// loginWithCode logs in with code, expecting it to succeed.
+func loginWithCode(t *testing.T, r http.Handler, code string) loginResponse {
+	rr := doLogin(t, r, code)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp loginResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	require.NoError(t, err)
+	return resp
+}
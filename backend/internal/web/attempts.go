@@ -0,0 +1,50 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/throttle"
+)
+
+// loginAttemptResponse has either the code that was tried or, for a
+// wrong password, the account it was tried for.
+type loginAttemptResponse struct {
+	Client  string         `json:"client"`
+	Code    string         `json:"code,omitempty"`
+	Account *simpleAccount `json:"account,omitempty"`
+	Time    time.Time      `json:"time"`
+}
+
+type loginAttemptsResponse struct {
+	Attempts []loginAttemptResponse `json:"attempts"`
+}
+
+// getLoginAttempts lists the latest failed logins, newest first, so
+// teachers can tell a mistyped code from someone guessing.
+func getLoginAttempts(accountStore account.Store, guard *throttle.Guard) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		resp := loginAttemptsResponse{Attempts: []loginAttemptResponse{}}
+		for _, a := range guard.Attempts() {
+			attempt := loginAttemptResponse{
+				Client: a.Client,
+				Code:   a.Code,
+				Time:   a.Time,
+			}
+			if a.AccountID != "" {
+				attempt.Account = &simpleAccount{ID: a.AccountID}
+				if acc, err := accountStore.GetAccount(req.Context(), a.AccountID); err == nil {
+					attempt.Account.Name = acc.Name()
+				}
+			}
+			resp.Attempts = append(resp.Attempts, attempt)
+		}
+		err := json.NewEncoder(w).Encode(resp)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLoginAttempts(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	student := givenAccount(t, accountStore, account.NewStudent("Test Login"))
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, store.NewInMemory(), classroom.NewInMemoryStore(), newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
+
+	rr := doLogin(t, r, "NOPE")
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	rr = doLogin(t, r, "STILL")
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	loginWithCode(t, r, student.Code())
+
+	t.Run("should list failed attempts, newest first", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodGet, "/login/attempts", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp loginAttemptsResponse
+		err := json.NewDecoder(rr.Body).Decode(&resp)
+		require.NoError(t, err)
+		require.Len(t, resp.Attempts, 2)
+		assert.Equal(t, "STILL", resp.Attempts[0].Code)
+		assert.Equal(t, "NOPE", resp.Attempts[1].Code)
+		assert.False(t, resp.Attempts[0].Time.IsZero())
+	})
+
+	t.Run("should return forbidden for students", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, student, http.MethodGet, "/login/attempts", "")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+}
@@ -0,0 +1,122 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/session"
+	"github.com/go-chi/chi/v5"
+)
+
+type contextKey string
+
+const accountKey contextKey = "account"
+
+// authenticate rejects any request without a valid session token in its
+// Authorization header. The account the token was issued to is put on
+// the request context for handlers to retrieve with accountFromContext.
+func authenticate(accountStore account.Store, sessions *session.Manager) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			token := bearerToken(req)
+			if token == "" {
+				writeError(w, errUnauthorised)
+				return
+			}
+			id, err := sessions.Verify(token)
+			if err != nil {
+				writeError(w, errUnauthorised)
+				return
+			}
+			acc, err := accountStore.GetAccount(req.Context(), id)
+			if errors.Is(err, account.ErrNotFound) {
+				// The account may have been removed since the token
+				// was issued.
+				writeError(w, errUnauthorised)
+				return
+			}
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			credential, err := sessionCredential(req.Context(), accountStore, acc)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			if !sessions.IssuedFor(token, credential) {
+				// The login code or password has changed since.
+				writeError(w, errUnauthorised)
+				return
+			}
+			ctx := context.WithValue(req.Context(), accountKey, acc)
+			next.ServeHTTP(w, req.WithContext(ctx))
+		})
+	}
+}
+
+// sessionCredential is what sessions for acc are bound to: its login
+// code and, if it has one, its password hash. Changing either logs out
+// every session.
+func sessionCredential(ctx context.Context, accountStore account.Store, acc account.Account) (string, error) {
+	hash, err := accountStore.PasswordHash(ctx, acc.ID())
+	if err != nil {
+		return "", err
+	}
+	return acc.Code() + string(hash), nil
+}
+
+func bearerToken(req *http.Request) string {
+	header := req.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(header[len(prefix):])
+}
+
+// accountFromContext returns the account authenticated for the request.
+func accountFromContext(ctx context.Context) account.Account {
+	acc, _ := ctx.Value(accountKey).(account.Account)
+	return acc
+}
+
+// onlyTeachers forbids the request unless it was made by a teacher.
+func onlyTeachers(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if accountFromContext(req.Context()).Role() != account.RoleTeacher {
+			writeError(w, errForbidden)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// onlySelf guards routes on an account's login, such as its code and
+// password, which only its owner may change. Even other teachers are
+// kept out, as changing them would let them log in as the owner.
+func onlySelf(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if accountFromContext(req.Context()).ID() != chi.URLParam(req, "id") {
+			writeError(w, errForbidden)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// onlySelfOrTeacher guards /students/{id} routes: teachers may access
+// any student, while a student may only access their own resources.
+func onlySelfOrTeacher(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		acc := accountFromContext(req.Context())
+		if acc.Role() != account.RoleTeacher && acc.ID() != chi.URLParam(req, "id") {
+			writeError(w, errForbidden)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
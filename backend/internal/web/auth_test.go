@@ -0,0 +1,81 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/session"
+	"github.com/Manchester-Dev/medlock/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthentication(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	student := account.NewStudent("Test Login")
+	err := accountStore.SaveAccount(context.Background(), student)
+	require.NoError(t, err)
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, store.NewInMemory(), classroom.NewInMemoryStore(), newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
+
+	get := func(t *testing.T, authorization string) int {
+		req, err := http.NewRequest(http.MethodGet, "/achievements", nil)
+		require.NoError(t, err)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	t.Run("should return unauthorised without a token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, get(t, ""))
+	})
+
+	t.Run("should return unauthorised for a malformed token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, get(t, "Bearer not-a-token"))
+	})
+
+	t.Run("should return unauthorised for a token signed with another secret", func(t *testing.T) {
+		other := session.NewManager([]byte("another secret"), time.Hour)
+		token, err := other.Issue(student.ID(), student.Code())
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, get(t, "Bearer "+token))
+	})
+
+	t.Run("should return unauthorised for a token of an unknown account", func(t *testing.T) {
+		token, err := sessions.Issue("not-an-account", student.Code())
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, get(t, "Bearer "+token))
+	})
+
+	t.Run("should return unauthorised when token is not a bearer token", func(t *testing.T) {
+		token, err := sessions.Issue(student.ID(), student.Code())
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, get(t, "Basic "+token))
+	})
+
+	t.Run("should allow requests with a valid token", func(t *testing.T) {
+		token, err := sessions.Issue(student.ID(), student.Code())
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, get(t, "Bearer "+token))
+	})
+
+	t.Run("should put the authenticated account on the request context", func(t *testing.T) {
+		var found account.Account
+		handler := authenticate(accountStore, sessions)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			found = accountFromContext(req.Context())
+		}))
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		require.NoError(t, err)
+		authorise(t, req, sessions, student)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		assert.Equal(t, student, found)
+	})
+}
@@ -0,0 +1,277 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/cards"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/go-chi/chi/v5"
+)
+
+type createClassroomRequest struct {
+	Name string `json:"name"`
+}
+
+type createClassroomResponse struct {
+	ID string `json:"id"`
+}
+
+func createClassroom(classroomStore classroom.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var classReq createClassroomRequest
+		err := json.NewDecoder(req.Body).Decode(&classReq)
+		if err != nil {
+			writeError(w, errInvalidJSON)
+			return
+		}
+		if strings.TrimSpace(classReq.Name) == "" {
+			writeError(w, invalidField("name", "is required"))
+			return
+		}
+		teacher := accountFromContext(req.Context())
+		id, err := classroomStore.CreateClassroom(req.Context(), classReq.Name, teacher.ID())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		err = json.NewEncoder(w).Encode(createClassroomResponse{ID: id})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+type classroomsResponse struct {
+	Classrooms []classroom.Classroom `json:"classes"`
+}
+
+func getTeacherClassrooms(classroomStore classroom.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		teacher := accountFromContext(req.Context())
+		cc, err := classroomStore.GetTeacherClassrooms(req.Context(), teacher.ID())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		err = json.NewEncoder(w).Encode(classroomsResponse{Classrooms: cc})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+// teacherClassroom returns the class in the URL of req if the teacher
+// making it runs the class. Other teachers' classes look the same as
+// classes that do not exist.
+func teacherClassroom(req *http.Request, classroomStore classroom.Store) (*classroom.Classroom, error) {
+	c, err := classroomStore.GetClassroom(req.Context(), chi.URLParam(req, "class"))
+	if err != nil {
+		return nil, err
+	}
+	if c.TeacherID != accountFromContext(req.Context()).ID() {
+		return nil, classroom.ErrNotFound
+	}
+	return c, nil
+}
+
+type simpleAccount struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type classroomStudentsResponse struct {
+	Students []simpleAccount `json:"students"`
+}
+
+func getClassroomStudents(accountStore account.Store, classroomStore classroom.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		c, err := teacherClassroom(req, classroomStore)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		students := make([]simpleAccount, 0, len(c.StudentIDs))
+		for _, id := range c.StudentIDs {
+			acc, err := accountStore.GetAccount(req.Context(), id)
+			if errors.Is(err, account.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			students = append(students, simpleAccount{ID: acc.ID(), Name: acc.Name()})
+		}
+		err = json.NewEncoder(w).Encode(classroomStudentsResponse{Students: students})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+func addClassroomStudent(accountStore account.Store, achievementStore achievements.Store, classroomStore classroom.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		c, err := teacherClassroom(req, classroomStore)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		student, err := accountStore.GetAccount(req.Context(), chi.URLParam(req, "student"))
+		if errors.Is(err, account.ErrNotFound) {
+			writeError(w, errStudentNotFound)
+			return
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if student.Role() != account.RoleStudent {
+			writeError(w, errNotStudent)
+			return
+		}
+		err = classroomStore.AddStudent(req.Context(), c.ID, student.ID())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		for _, achievementID := range c.AchievementIDs {
+			err = achievements.Give(req.Context(), achievementStore, student.ID(), achievementID, accountFromContext(req.Context()).ID())
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+		}
+	}
+}
+
+func removeClassroomStudent(classroomStore classroom.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		c, err := teacherClassroom(req, classroomStore)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		err = classroomStore.RemoveStudent(req.Context(), c.ID, chi.URLParam(req, "student"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+func assignClassroomAchievement(achievementStore achievements.Store, classroomStore classroom.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		c, err := teacherClassroom(req, classroomStore)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		achievementID := chi.URLParam(req, "achievement")
+		if err := requireAchievement(req.Context(), achievementStore, achievementID); err != nil {
+			writeError(w, err)
+			return
+		}
+		err = classroomStore.AssignAchievement(req.Context(), c.ID, achievementID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		for _, studentID := range c.StudentIDs {
+			err = achievements.Give(req.Context(), achievementStore, studentID, achievementID, accountFromContext(req.Context()).ID())
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+		}
+	}
+}
+
+// getLoginCards sends a PDF of login cards for the students of a class.
+// Given a qr query of the frontend's login page, each card also has a QR
+// code which opens it with the student's code filled in.
+func getLoginCards(accountStore account.Store, classroomStore classroom.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		c, err := teacherClassroom(req, classroomStore)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		var loginURL *url.URL
+		if qr := req.URL.Query().Get("qr"); qr != "" {
+			loginURL, err = url.Parse(qr)
+			if err != nil || (loginURL.Scheme != "https" && loginURL.Scheme != "http") || loginURL.Host == "" {
+				writeError(w, invalidField("qr", "must be an http or https URL"))
+				return
+			}
+		}
+		students := make([]cards.Card, 0, len(c.StudentIDs))
+		for _, id := range c.StudentIDs {
+			acc, err := accountStore.GetAccount(req.Context(), id)
+			if errors.Is(err, account.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			students = append(students, cards.Card{Name: acc.Name(), Code: acc.Code()})
+		}
+		sort.Slice(students, func(i, j int) bool {
+			return students[i].Name < students[j].Name
+		})
+		var pdf bytes.Buffer
+		err = cards.Render(&pdf, c.Name, students, loginURL)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="login-cards.pdf"`)
+		w.Write(pdf.Bytes())
+	}
+}
+
+// teacherStudentIDs returns the IDs of the students in any of the
+// classes of the teacher with teacherID, each once.
+func teacherStudentIDs(ctx context.Context, classroomStore classroom.Store, teacherID string) ([]string, error) {
+	classes, err := classroomStore.GetTeacherClassrooms(ctx, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	seen := make(map[string]bool)
+	for _, c := range classes {
+		for _, id := range c.StudentIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// studentNames looks up the name of every student at once, by ID.
+func studentNames(ctx context.Context, accountStore account.Store) (map[string]string, error) {
+	students, err := accountStore.GetAccounts(ctx, account.RoleStudent)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(students))
+	for _, s := range students {
+		names[s.ID()] = s.Name()
+	}
+	return names, nil
+}
@@ -0,0 +1,216 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/session"
+	"github.com/Manchester-Dev/medlock/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassrooms(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	achievementStore := store.NewInMemory()
+	classroomStore := classroom.NewInMemoryStore()
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	student := givenAccount(t, accountStore, account.NewStudent("Student A"))
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroomStore, newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
+
+	t.Run("should return forbidden for students", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, student, http.MethodPost, "/classes", `{"name": "Class 3B"}`)
+		require.Equal(t, http.StatusForbidden, rr.Code)
+		rr = doRequest(t, r, sessions, student, http.MethodGet, "/classes", "")
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should return bad request for a class without a name", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, "/classes", `{"name": " "}`)
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	var classID string
+	t.Run("should create a class for the teacher", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, "/classes", `{"name": "Class 3B"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp createClassroomResponse
+		err := json.NewDecoder(rr.Body).Decode(&resp)
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.ID)
+		classID = resp.ID
+
+		rr = doRequest(t, r, sessions, teacher, http.MethodGet, "/classes", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var classes classroomsResponse
+		err = json.NewDecoder(rr.Body).Decode(&classes)
+		require.NoError(t, err)
+		require.Len(t, classes.Classrooms, 1)
+		assert.Equal(t, "Class 3B", classes.Classrooms[0].Name)
+		assert.Equal(t, teacher.ID(), classes.Classrooms[0].TeacherID)
+	})
+
+	t.Run("should add a student to the class", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPut, "/classes/"+classID+"/students/"+student.ID(), "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, []simpleAccount{{ID: student.ID(), Name: "Student A"}}, getClassroomStudentsFromAPI(t, r, sessions, teacher, classID))
+	})
+
+	t.Run("should return not found when adding unknown students or to unknown classes", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPut, "/classes/"+classID+"/students/not-a-student", "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodPut, "/classes/not-a-class/students/"+student.ID(), "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodGet, "/classes/not-a-class/students", "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should return bad request when adding a teacher as a student", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPut, "/classes/"+classID+"/students/"+teacher.ID(), "")
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should give the whole class an assigned achievement", func(t *testing.T) {
+		started := givenAchievement(t, achievementStore)
+		require.NoError(t, achievementStore.AddProgression(context.Background(), achievements.StudentAchievement{
+			AchievementID: started,
+			StudentID:     student.ID(),
+			Progress:      achievements.Started,
+		}, student.ID()))
+		fresh := givenAchievement(t, achievementStore)
+
+		for _, id := range []string{started, fresh} {
+			rr := doRequest(t, r, sessions, teacher, http.MethodPut, "/classes/"+classID+"/achievements/"+id, "")
+			require.Equal(t, http.StatusOK, rr.Code)
+		}
+
+		studentAchievements, err := achievementStore.GetStudentAchievements(context.Background(), student.ID())
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []achievements.StudentAchievement{
+			{AchievementID: started, StudentID: student.ID(), Progress: achievements.Started},
+			{AchievementID: fresh, StudentID: student.ID(), Progress: achievements.NotStarted},
+		}, studentAchievements)
+
+		latecomer := givenAccount(t, accountStore, account.NewStudent("Student B"))
+		rr := doRequest(t, r, sessions, teacher, http.MethodPut, "/classes/"+classID+"/students/"+latecomer.ID(), "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		studentAchievements, err = achievementStore.GetStudentAchievements(context.Background(), latecomer.ID())
+		require.NoError(t, err)
+		assert.Len(t, studentAchievements, 2)
+	})
+
+	t.Run("should return not found when assigning an unknown achievement", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPut, "/classes/"+classID+"/achievements/not-an-achievement", "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should print login cards for the class", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodGet, "/classes/"+classID+"/cards?qr=https://medlock.example/login", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/pdf", rr.Header().Get("Content-Type"))
+		assert.True(t, bytes.HasPrefix(rr.Body.Bytes(), []byte("%PDF-")))
+
+		rr = doRequest(t, r, sessions, teacher, http.MethodGet, "/classes/"+classID+"/cards?qr=javascript:alert(1)", "")
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodGet, "/classes/not-a-class/cards", "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		rr = doRequest(t, r, sessions, student, http.MethodGet, "/classes/"+classID+"/cards", "")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should not let other teachers see or change the class", func(t *testing.T) {
+		colleague := givenAccount(t, accountStore, account.NewTeacher("Other Teacher"))
+		achievementID := givenAchievement(t, achievementStore)
+		for _, route := range []struct {
+			method string
+			url    string
+		}{
+			{http.MethodGet, "/classes/" + classID + "/students"},
+			{http.MethodGet, "/classes/" + classID + "/cards"},
+			{http.MethodGet, "/classes/" + classID + "/leaderboard"},
+			{http.MethodPut, "/classes/" + classID + "/students/" + student.ID()},
+			{http.MethodDelete, "/classes/" + classID + "/students/" + student.ID()},
+			{http.MethodPut, "/classes/" + classID + "/achievements/" + achievementID},
+			{http.MethodGet, "/dashboard?class=" + classID},
+		} {
+			rr := doRequest(t, r, sessions, colleague, route.method, route.url, "")
+			assert.Equal(t, http.StatusNotFound, rr.Code, route.method+" "+route.url)
+		}
+		c, err := classroomStore.GetClassroom(context.Background(), classID)
+		require.NoError(t, err)
+		assert.True(t, c.HasStudent(student.ID()))
+		assert.NotContains(t, c.AchievementIDs, achievementID)
+	})
+
+	t.Run("should remove a student from the class", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodDelete, "/classes/"+classID+"/students/"+student.ID(), "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		for _, s := range getClassroomStudentsFromAPI(t, r, sessions, teacher, classID) {
+			assert.NotEqual(t, student.ID(), s.ID)
+		}
+	})
+}
+
+func getClassroomStudentsFromAPI(t *testing.T, r http.Handler, sessions *session.Manager, teacher account.Account, classID string) []simpleAccount {
+	rr := doRequest(t, r, sessions, teacher, http.MethodGet, "/classes/"+classID+"/students", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp classroomStudentsResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	require.NoError(t, err)
+	return resp.Students
+}
+
+// givenClassroom creates a class run by the teacher with teacherID,
+// failing the test if it cannot.
+func givenClassroom(t *testing.T, classroomStore classroom.Store, name string, teacherID string) string {
+	t.Helper()
+	id, err := classroomStore.CreateClassroom(context.Background(), name, teacherID)
+	require.NoError(t, err)
+	return id
+}
+
+// doRequest sends a request to r authenticated as acc, with body as
+// its JSON body when not empty.
+func doRequest(t *testing.T, r http.Handler, sessions *session.Manager, acc account.Account, method, url, body string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	authorise(t, req, sessions, acc)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestAddingClassroomStudentFailure(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	classroomStore := classroom.NewInMemoryStore()
+	classID := givenClassroom(t, classroomStore, "Class 3B", teacher.ID())
+	sessions := newTestSessions()
+	r := NewRouter(brokenAccount{Store: accountStore, id: "broken"}, store.NewInMemory(), classroomStore, newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
+
+	rr := doRequest(t, r, sessions, teacher, http.MethodPut, "/classes/"+classID+"/students/broken", "")
+	assert.Equal(t, http.StatusInternalServerError, rr.Code, "a failed lookup is not a missing student")
+}
+
+// brokenAccount fails to look up the account with id, as a store whose
+// database is down would.
+type brokenAccount struct {
+	account.Store
+	id string
+}
+
+func (b brokenAccount) GetAccount(ctx context.Context, id string) (account.Account, error) {
+	if id == b.id {
+		return nil, errors.New("disk on fire")
+	}
+	return b.Store.GetAccount(ctx, id)
+}
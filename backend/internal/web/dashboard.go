@@ -0,0 +1,139 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+)
+
+// dashboardResponse is a students × achievements matrix. Each student's
+// Progress lines up with Achievements, holding null where the student
+// has not been given that achievement.
+type dashboardResponse struct {
+	Achievements []simpleAchievement `json:"achievements"`
+	Students     []studentProgress   `json:"students"`
+}
+
+type studentProgress struct {
+	ID       string                   `json:"id"`
+	Name     string                   `json:"name"`
+	Progress []*achievements.Progress `json:"progress"`
+}
+
+func getDashboard(accountStore account.Store, achievementStore achievements.Store, classroomStore classroom.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		teacher := accountFromContext(req.Context())
+		students, err := dashboardStudents(req.Context(), accountStore, classroomStore, teacher.ID(), req.URL.Query().Get("class"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		sort.Slice(students, func(i, j int) bool {
+			return students[i].Name() < students[j].Name()
+		})
+		ids := make([]string, len(students))
+		for i, s := range students {
+			ids[i] = s.ID()
+		}
+		progressions, err := achievementStore.GetAchievementsForStudents(req.Context(), ids)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		achvs, err := achievementStore.GetAllAchievements(req.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		err = json.NewEncoder(w).Encode(toDashboardResponse(achvs, students, progressions))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+// dashboardStudents returns the students of the given class of the
+// teacher with teacherID, or of all their classes when no class is
+// given.
+func dashboardStudents(ctx context.Context, accountStore account.Store, classroomStore classroom.Store, teacherID string, classID string) ([]account.Account, error) {
+	var classes []classroom.Classroom
+	if classID == "" {
+		var err error
+		classes, err = classroomStore.GetTeacherClassrooms(ctx, teacherID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		c, err := classroomStore.GetClassroom(ctx, classID)
+		if err != nil {
+			return nil, err
+		}
+		if c.TeacherID != teacherID {
+			return nil, classroom.ErrNotFound
+		}
+		classes = []classroom.Classroom{*c}
+	}
+	members := make(map[string]bool)
+	for _, c := range classes {
+		for _, id := range c.StudentIDs {
+			members[id] = true
+		}
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	all, err := accountStore.GetAccounts(ctx, account.RoleStudent)
+	if err != nil {
+		return nil, err
+	}
+	students := make([]account.Account, 0, len(members))
+	for _, acc := range all {
+		if members[acc.ID()] {
+			students = append(students, acc)
+		}
+	}
+	return students, nil
+}
+
+func toDashboardResponse(achvs []achievements.Achievement, students []account.Account, progressions []achievements.StudentAchievement) dashboardResponse {
+	sort.Slice(achvs, func(i, j int) bool {
+		return achvs[i].Name < achvs[j].Name
+	})
+	columns := make(map[string]int, len(achvs))
+	resp := dashboardResponse{
+		Achievements: make([]simpleAchievement, len(achvs)),
+		Students:     make([]studentProgress, len(students)),
+	}
+	for i, a := range achvs {
+		columns[a.ID] = i
+		resp.Achievements[i] = simpleAchievement{Name: a.Name, ID: a.ID}
+	}
+	rows := make(map[string]int, len(students))
+	for i, s := range students {
+		rows[s.ID()] = i
+		resp.Students[i] = studentProgress{
+			ID:       s.ID(),
+			Name:     s.Name(),
+			Progress: make([]*achievements.Progress, len(achvs)),
+		}
+	}
+	for _, p := range progressions {
+		row, ok := rows[p.StudentID]
+		if !ok {
+			continue
+		}
+		col, ok := columns[p.AchievementID]
+		if !ok {
+			continue
+		}
+		progress := p.Progress
+		resp.Students[row].Progress[col] = &progress
+	}
+	return resp
+}
@@ -0,0 +1,92 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboard(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	achievementStore := store.NewInMemory()
+	classroomStore := classroom.NewInMemoryStore()
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	alice := givenAccount(t, accountStore, account.NewStudent("Alice"))
+	bob := givenAccount(t, accountStore, account.NewStudent("Bob"))
+	compost, err := achievementStore.CreateAchievement(context.Background(), achievements.Achievement{Name: "Start a Compost Heap"})
+	require.NoError(t, err)
+	seeds, err := achievementStore.CreateAchievement(context.Background(), achievements.Achievement{Name: "Plant some Seeds"})
+	require.NoError(t, err)
+	require.NoError(t, achievementStore.AddProgression(context.Background(), achievements.StudentAchievement{AchievementID: compost, StudentID: alice.ID(), Progress: achievements.Finished}, alice.ID()))
+	require.NoError(t, achievementStore.AddProgression(context.Background(), achievements.StudentAchievement{AchievementID: seeds, StudentID: alice.ID(), Progress: achievements.NotStarted}, alice.ID()))
+	require.NoError(t, achievementStore.AddProgression(context.Background(), achievements.StudentAchievement{AchievementID: seeds, StudentID: bob.ID(), Progress: achievements.Started}, bob.ID()))
+	classID := givenClassroom(t, classroomStore, "Class 3B", teacher.ID())
+	require.NoError(t, classroomStore.AddStudent(context.Background(), classID, bob.ID()))
+	otherClassID := givenClassroom(t, classroomStore, "Class 4A", teacher.ID())
+	require.NoError(t, classroomStore.AddStudent(context.Background(), otherClassID, alice.ID()))
+	carol := givenAccount(t, accountStore, account.NewStudent("Carol"))
+	colleague := givenAccount(t, accountStore, account.NewTeacher("Other Teacher"))
+	colleagueClassID := givenClassroom(t, classroomStore, "Class 5C", colleague.ID())
+	require.NoError(t, classroomStore.AddStudent(context.Background(), colleagueClassID, carol.ID()))
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroomStore, newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
+
+	progress := func(p achievements.Progress) *achievements.Progress {
+		return &p
+	}
+
+	t.Run("should return forbidden for students", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, alice, http.MethodGet, "/dashboard", "")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should return the progress of every student in the teacher's classes on every achievement", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodGet, "/dashboard", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp dashboardResponse
+		err := json.NewDecoder(rr.Body).Decode(&resp)
+		require.NoError(t, err)
+		assert.Equal(t, dashboardResponse{
+			Achievements: []simpleAchievement{
+				{ID: seeds, Name: "Plant some Seeds"},
+				{ID: compost, Name: "Start a Compost Heap"},
+			},
+			Students: []studentProgress{
+				{ID: alice.ID(), Name: "Alice", Progress: []*achievements.Progress{progress(achievements.NotStarted), progress(achievements.Finished)}},
+				{ID: bob.ID(), Name: "Bob", Progress: []*achievements.Progress{progress(achievements.Started), nil}},
+			},
+		}, resp)
+	})
+
+	t.Run("should only return students in the given class", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodGet, "/dashboard?class="+classID, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp dashboardResponse
+		err := json.NewDecoder(rr.Body).Decode(&resp)
+		require.NoError(t, err)
+		require.Len(t, resp.Students, 1)
+		assert.Equal(t, bob.ID(), resp.Students[0].ID)
+	})
+
+	t.Run("should not return students of other teachers' classes", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, colleague, http.MethodGet, "/dashboard", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp dashboardResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		require.Len(t, resp.Students, 1)
+		assert.Equal(t, carol.ID(), resp.Students[0].ID)
+	})
+
+	t.Run("should return not found for an unknown class", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodGet, "/dashboard?class=not-a-class", "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
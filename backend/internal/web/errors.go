@@ -0,0 +1,164 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/evidence"
+)
+
+// apiError is how a failed request is reported to clients:
+//
+//	{"error": {"code": "achievement_not_found", "message": "...", "fields": [...]}}
+//
+// Code is for the frontend to tell failures apart, Message is for
+// people, and Fields points out what was wrong with the request.
+type apiError struct {
+	Status  int          `json:"-"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Fields  []fieldError `json:"fields,omitempty"`
+}
+
+// fieldError is a problem with one field of a request, or with one row
+// of an uploaded CSV.
+type fieldError struct {
+	Field   string `json:"field,omitempty"`
+	Row     int    `json:"row,omitempty"`
+	Message string `json:"message"`
+}
+
+type errorResponse struct {
+	Error *apiError `json:"error"`
+}
+
+func (e *apiError) Error() string {
+	return e.Message
+}
+
+var (
+	errInvalidJSON         = &apiError{Status: http.StatusBadRequest, Code: "invalid_json", Message: "request body is not valid JSON"}
+	errInvalidCSV          = &apiError{Status: http.StatusBadRequest, Code: "invalid_csv", Message: "request body is not a readable CSV file"}
+	errUnauthorised        = &apiError{Status: http.StatusUnauthorized, Code: "unauthorized", Message: "log in to do this"}
+	errInvalidLogin        = &apiError{Status: http.StatusUnauthorized, Code: "invalid_login", Message: "login code or password is wrong"}
+	errForbidden           = &apiError{Status: http.StatusForbidden, Code: "forbidden", Message: "you are not allowed to do this"}
+	errRouteNotFound       = &apiError{Status: http.StatusNotFound, Code: "not_found", Message: "there is nothing at this address"}
+	errMethodNotAllowed    = &apiError{Status: http.StatusMethodNotAllowed, Code: "method_not_allowed", Message: "this address does not support the request method"}
+	errWrongPassword       = &apiError{Status: http.StatusForbidden, Code: "wrong_password", Message: "current password is wrong"}
+	errAccountNotFound     = &apiError{Status: http.StatusNotFound, Code: "account_not_found", Message: "account not found"}
+	errStudentNotFound     = &apiError{Status: http.StatusNotFound, Code: "student_not_found", Message: "student not found"}
+	errTeacherNotFound     = &apiError{Status: http.StatusNotFound, Code: "teacher_not_found", Message: "teacher not found"}
+	errAchievementNotFound = &apiError{Status: http.StatusNotFound, Code: "achievement_not_found", Message: "achievement not found"}
+	errRewardNotFound      = &apiError{Status: http.StatusNotFound, Code: "reward_not_found", Message: "reward not found"}
+	errClassNotFound       = &apiError{Status: http.StatusNotFound, Code: "class_not_found", Message: "class not found"}
+	errEvidenceNotFound    = &apiError{Status: http.StatusNotFound, Code: "evidence_not_found", Message: "evidence not found"}
+	errNotStudent          = &apiError{Status: http.StatusBadRequest, Code: "not_a_student", Message: "account is not a student"}
+	errDeleteSelf          = &apiError{Status: http.StatusBadRequest, Code: "cannot_delete_self", Message: "teachers cannot delete their own account"}
+	errCodeConflict        = &apiError{Status: http.StatusConflict, Code: "code_conflict", Message: "login code is already in use"}
+	errNotSubmitted        = &apiError{Status: http.StatusConflict, Code: "not_submitted", Message: "achievement has not been submitted for review"}
+	errInsufficientPoints  = &apiError{Status: http.StatusConflict, Code: "insufficient_points", Message: "not enough points"}
+	errFileTooLarge        = &apiError{Status: http.StatusRequestEntityTooLarge, Code: "file_too_large", Message: "file is too large"}
+	errUnsupportedFile     = &apiError{Status: http.StatusUnsupportedMediaType, Code: "unsupported_file_type", Message: "file type is not supported"}
+	errTooManyAttempts     = &apiError{Status: http.StatusTooManyRequests, Code: "too_many_attempts", Message: "too many failed logins, try again later"}
+	errCancelled           = &apiError{Status: statusClientClosedRequest, Code: "request_cancelled", Message: "request was cancelled"}
+	errTimeout             = &apiError{Status: http.StatusServiceUnavailable, Code: "timeout", Message: "request took too long, try again"}
+	errInternal            = &apiError{Status: http.StatusInternalServerError, Code: "internal_error", Message: "something went wrong, try again"}
+)
+
+// statusClientClosedRequest is the non-standard status nginx logs for a
+// client which went away before it was answered. Nobody receives it, but
+// it keeps such requests apart from real failures in access logs.
+const statusClientClosedRequest = 499
+
+// accountNotFound is the error for an account with role that does not
+// exist.
+func accountNotFound(role string) *apiError {
+	switch role {
+	case account.RoleStudent:
+		return errStudentNotFound
+	case account.RoleTeacher:
+		return errTeacherNotFound
+	default:
+		return errAccountNotFound
+	}
+}
+
+// invalidFields reports a request whose fields failed validation.
+func invalidFields(fields ...fieldError) *apiError {
+	return &apiError{
+		Status:  http.StatusBadRequest,
+		Code:    "invalid_request",
+		Message: "request has invalid fields",
+		Fields:  fields,
+	}
+}
+
+// invalidField reports a request with one field that failed validation.
+func invalidField(field string, message string) *apiError {
+	return invalidFields(fieldError{Field: field, Message: message})
+}
+
+// writeError responds with err in an error envelope. Errors from the
+// stores are reported as their apiError, and anything unexpected is
+// logged and reported as an internal error.
+func writeError(w http.ResponseWriter, err error) {
+	e := toAPIError(err)
+	if e == errInternal {
+		log.Printf("web: %v", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Status)
+	json.NewEncoder(w).Encode(errorResponse{Error: e})
+}
+
+func toAPIError(err error) *apiError {
+	var (
+		apiErr    *apiError
+		noCode    *account.CodeDoesNotExistError
+		noAccount *account.AccountDoesNotExistError
+	)
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr
+	case errors.As(err, &noCode):
+		return errInvalidLogin
+	case errors.As(err, &noAccount):
+		return errAccountNotFound
+	case errors.Is(err, account.ErrConflict):
+		return errCodeConflict
+	case errors.Is(err, account.ErrPasswordTooShort):
+		return invalidField("password", fmt.Sprintf("must be at least %d characters", account.MinPasswordLength))
+	case errors.Is(err, account.ErrPasswordTooLong):
+		return invalidField("password", fmt.Sprintf("must be at most %d characters", account.MaxPasswordLength))
+	case errors.Is(err, achievements.ErrUnknownCursor):
+		return invalidField("cursor", "does not match a listed achievement")
+	case errors.Is(err, achievements.ErrAchievementNotFound):
+		return errAchievementNotFound
+	case errors.Is(err, achievements.ErrRewardNotFound):
+		return errRewardNotFound
+	case errors.Is(err, achievements.ErrNotSubmitted):
+		return errNotSubmitted
+	case errors.Is(err, achievements.ErrInsufficientPoints):
+		return errInsufficientPoints
+	case errors.Is(err, classroom.ErrNotFound):
+		return errClassNotFound
+	case errors.Is(err, evidence.ErrNotFound):
+		return errEvidenceNotFound
+	case errors.Is(err, evidence.ErrTooLarge):
+		return errFileTooLarge
+	case errors.Is(err, evidence.ErrUnsupportedContent):
+		return errUnsupportedFile
+	case errors.Is(err, context.Canceled):
+		return errCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		return errTimeout
+	}
+	return errInternal
+}
@@ -0,0 +1,147 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/evidence"
+	"github.com/Manchester-Dev/medlock/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorResponses(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	achievementStore := store.NewInMemory()
+	student := givenAccount(t, accountStore, account.NewStudent("Test Login"))
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroom.NewInMemoryStore(), newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
+	achievementID := givenAchievement(t, achievementStore)
+
+	t.Run("should describe the error in a JSON envelope", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/achievements", nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		var resp map[string]map[string]interface{}
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		assert.Equal(t, "unauthorized", resp["error"]["code"])
+		assert.NotEmpty(t, resp["error"]["message"])
+	})
+
+	t.Run("should tell apart what was not found", func(t *testing.T) {
+		url := fmt.Sprintf("/students/%s/achievements/%s/progress", student.ID(), "not-an-achievement")
+		rr := doRequest(t, r, sessions, student, http.MethodPut, url, `{"progress": "STARTED"}`)
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, "achievement_not_found", decodeError(t, rr).Code)
+
+		teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+		url = fmt.Sprintf("/students/%s/achievements/%s/progress", "not-a-student", achievementID)
+		rr = doRequest(t, r, sessions, teacher, http.MethodPut, url, `{"progress": "STARTED"}`)
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, "student_not_found", decodeError(t, rr).Code)
+
+		url = fmt.Sprintf("/students/%s/achievements/%s/progress", teacher.ID(), achievementID)
+		rr = doRequest(t, r, sessions, teacher, http.MethodPut, url, `{"progress": "STARTED"}`)
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, "student_not_found", decodeError(t, rr).Code)
+	})
+
+	t.Run("should use the envelope for unknown routes and methods", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, student, http.MethodGet, "/nowhere", "")
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "not_found", decodeError(t, rr).Code)
+
+		rr = doRequest(t, r, sessions, student, http.MethodGet, "/students/"+student.ID()+"/nowhere", "")
+		require.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Equal(t, "not_found", decodeError(t, rr).Code)
+
+		rr = doRequest(t, r, sessions, student, http.MethodPatch, "/login", "")
+		require.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+		assert.Equal(t, "method_not_allowed", decodeError(t, rr).Code)
+
+		rr = doRequest(t, r, sessions, student, http.MethodPost, "/students/"+student.ID()+"/achievements", "")
+		require.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+		assert.Equal(t, "method_not_allowed", decodeError(t, rr).Code)
+	})
+
+	t.Run("should point out invalid fields", func(t *testing.T) {
+		url := fmt.Sprintf("/students/%s/achievements/%s/progress", student.ID(), achievementID)
+		rr := doRequest(t, r, sessions, student, http.MethodPut, url, `{"progress": "DONE"}`)
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		apiErr := decodeError(t, rr)
+		assert.Equal(t, "invalid_request", apiErr.Code)
+		require.Len(t, apiErr.Fields, 1)
+		assert.Equal(t, "progress", apiErr.Fields[0].Field)
+
+		rr = doRequest(t, r, sessions, student, http.MethodPut, url, `{"progress": `)
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "invalid_json", decodeError(t, rr).Code)
+	})
+
+	t.Run("should give up on requests that have been cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/achievements", nil)
+		require.NoError(t, err)
+		authorise(t, req, sessions, student)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		assert.Equal(t, statusClientClosedRequest, rr.Code)
+		assert.Equal(t, "request_cancelled", decodeError(t, rr).Code)
+	})
+}
+
+func TestToAPIError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want *apiError
+	}{
+		{&account.CodeDoesNotExistError{}, errInvalidLogin},
+		{&account.AccountDoesNotExistError{}, errAccountNotFound},
+		{account.CodeConflictError{}, errCodeConflict},
+		{achievements.ErrAchievementNotFound, errAchievementNotFound},
+		{achievements.ErrRewardNotFound, errRewardNotFound},
+		{achievements.ErrUnknownCursor, invalidField("cursor", "does not match a listed achievement")},
+		{achievements.ErrNotSubmitted, errNotSubmitted},
+		{achievements.ErrInsufficientPoints, errInsufficientPoints},
+		{classroom.ErrNotFound, errClassNotFound},
+		{evidence.ErrNotFound, errEvidenceNotFound},
+		{evidence.ErrTooLarge, errFileTooLarge},
+		{evidence.ErrUnsupportedContent, errUnsupportedFile},
+		{fmt.Errorf("redeeming: %w", achievements.ErrRewardNotFound), errRewardNotFound},
+		{errForbidden, errForbidden},
+		{context.Canceled, errCancelled},
+		{fmt.Errorf("listing: %w", context.DeadlineExceeded), errTimeout},
+		{errors.New("disk on fire"), errInternal},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, toAPIError(test.err), test.err.Error())
+	}
+
+	t.Run("should not reveal unexpected errors", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		writeError(rr, errors.New("disk on fire"))
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.NotContains(t, rr.Body.String(), "disk on fire")
+	})
+}
+
+// decodeError reads the error envelope of a failed response.
+func decodeError(t *testing.T, rr *httptest.ResponseRecorder) *apiError {
+	var resp errorResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	require.NotNil(t, resp.Error)
+	return resp.Error
+}
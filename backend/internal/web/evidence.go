@@ -0,0 +1,145 @@
+package web
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/evidence"
+	"github.com/go-chi/chi/v5"
+)
+
+// multipartOverhead allows for the boundaries and headers around an
+// uploaded file on top of the file itself.
+const multipartOverhead = 64 << 10
+
+type uploadEvidenceResponse struct {
+	ID string `json:"id"`
+}
+
+// uploadEvidence attaches the file sent in the "file" field of a
+// multipart form to a student's achievement.
+func uploadEvidence(accountStore account.Store, achievementStore achievements.Store, locker *evidence.Locker) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+		if err := requireStudent(req.Context(), accountStore, id); err != nil {
+			writeError(w, err)
+			return
+		}
+		achievementID := chi.URLParam(req, "achievement")
+		if err := requireAchievement(req.Context(), achievementStore, achievementID); err != nil {
+			writeError(w, err)
+			return
+		}
+		maxRequest := locker.MaxSize() + multipartOverhead
+		if req.ContentLength > maxRequest {
+			writeError(w, errFileTooLarge)
+			return
+		}
+		req.Body = http.MaxBytesReader(w, req.Body, maxRequest)
+		mr, err := req.MultipartReader()
+		if err != nil {
+			writeError(w, invalidField("file", "must be sent as multipart/form-data"))
+			return
+		}
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				writeError(w, invalidField("file", "could not be read"))
+				return
+			}
+			if part.FormName() != "file" {
+				continue
+			}
+			e, err := locker.Upload(req.Context(), evidence.Evidence{
+				StudentID:     id,
+				AchievementID: achievementID,
+				FileName:      part.FileName(),
+				UploadedBy:    accountFromContext(req.Context()).ID(),
+			}, part)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			err = json.NewEncoder(w).Encode(uploadEvidenceResponse{ID: e.ID})
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			return
+		}
+		writeError(w, invalidField("file", "is required"))
+	}
+}
+
+type evidenceListResponse struct {
+	Evidence []evidence.Evidence `json:"evidence"`
+}
+
+func listEvidence(accountStore account.Store, locker *evidence.Locker) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+		if err := requireStudent(req.Context(), accountStore, id); err != nil {
+			writeError(w, err)
+			return
+		}
+		ee, err := locker.List(req.Context(), id, chi.URLParam(req, "achievement"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if ee == nil {
+			ee = []evidence.Evidence{}
+		}
+		err = json.NewEncoder(w).Encode(evidenceListResponse{Evidence: ee})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+// downloadEvidence sends the contents of an uploaded file as an
+// attachment, so browsers never render it in the app's origin.
+func downloadEvidence(locker *evidence.Locker) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		e, contents, err := locker.Open(req.Context(), chi.URLParam(req, "evidence"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		defer contents.Close()
+		if e.StudentID != chi.URLParam(req, "id") || e.AchievementID != chi.URLParam(req, "achievement") {
+			writeError(w, errEvidenceNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", e.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(e.Size, 10))
+		disposition := "attachment"
+		if e.FileName != "" {
+			disposition = mime.FormatMediaType(disposition, map[string]string{"filename": e.FileName})
+		}
+		w.Header().Set("Content-Disposition", disposition)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		io.Copy(w, contents)
+	}
+}
+
+// deleteEvidence removes an uploaded file, for when a student uploaded
+// the wrong one.
+func deleteEvidence(locker *evidence.Locker) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		err := locker.Remove(req.Context(), chi.URLParam(req, "id"), chi.URLParam(req, "achievement"), chi.URLParam(req, "evidence"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/evidence"
+	"github.com/Manchester-Dev/medlock/internal/session"
+	"github.com/Manchester-Dev/medlock/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPhoto starts with the signature content sniffing recognises as a
+// PNG image.
+var testPhoto = []byte("\x89PNG\x0D\x0A\x1A\x0Abottle caps")
+
+func TestEvidence(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	achievementStore := store.NewInMemory()
+	student := givenAccount(t, accountStore, account.NewStudent("Test Login"))
+	other := givenAccount(t, accountStore, account.NewStudent("Other Student"))
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	sessions := newTestSessions()
+	locker := evidence.NewLocker(evidence.NewInMemoryStore(), evidence.NewInMemoryBlobs(), evidence.Limits{
+		MaxSize:      1024,
+		ContentTypes: []string{"image/png"},
+	})
+	r := NewRouter(accountStore, achievementStore, classroom.NewInMemoryStore(), locker, newTestCodes(t), newTestGuard(), sessions)
+	achievementID := givenAchievement(t, achievementStore)
+	evidenceURL := fmt.Sprintf("/students/%s/achievements/%s/evidence", student.ID(), achievementID)
+
+	var evidenceID string
+	t.Run("should upload a photo", func(t *testing.T) {
+		rr := uploadFile(t, r, sessions, student, evidenceURL, "sculpture.png", testPhoto)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp uploadEvidenceResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		require.NotEmpty(t, resp.ID)
+		evidenceID = resp.ID
+	})
+
+	t.Run("should list uploaded evidence", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodGet, evidenceURL, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp evidenceListResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		require.Len(t, resp.Evidence, 1)
+		assert.Equal(t, evidenceID, resp.Evidence[0].ID)
+		assert.Equal(t, "sculpture.png", resp.Evidence[0].FileName)
+		assert.Equal(t, "image/png", resp.Evidence[0].ContentType)
+		assert.Equal(t, int64(len(testPhoto)), resp.Evidence[0].Size)
+		assert.Equal(t, student.ID(), resp.Evidence[0].UploadedBy)
+	})
+
+	t.Run("should download uploaded evidence as an attachment", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodGet, evidenceURL+"/"+evidenceID, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename=sculpture.png`, rr.Header().Get("Content-Disposition"))
+		assert.Equal(t, testPhoto, rr.Body.Bytes())
+	})
+
+	t.Run("should return not found for evidence of another achievement", func(t *testing.T) {
+		otherURL := fmt.Sprintf("/students/%s/achievements/%s/evidence/%s", student.ID(), givenAchievement(t, achievementStore), evidenceID)
+		rr := doRequest(t, r, sessions, teacher, http.MethodGet, otherURL, "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should return forbidden for another student", func(t *testing.T) {
+		rr := uploadFile(t, r, sessions, other, evidenceURL, "sculpture.png", testPhoto)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		rr = doRequest(t, r, sessions, other, http.MethodGet, evidenceURL+"/"+evidenceID, "")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should refuse files over the size limit", func(t *testing.T) {
+		large := append(append([]byte{}, testPhoto...), make([]byte, 1024)...)
+		rr := uploadFile(t, r, sessions, student, evidenceURL, "large.png", large)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+
+	t.Run("should refuse file types which are not allowed", func(t *testing.T) {
+		rr := uploadFile(t, r, sessions, student, evidenceURL, "sculpture.png", []byte("<html><script>alert(1)</script>"))
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	})
+
+	t.Run("should return bad request without a file", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, student, http.MethodPost, evidenceURL, `{"file": "sculpture.png"}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should delete uploaded evidence", func(t *testing.T) {
+		rr := uploadFile(t, r, sessions, student, evidenceURL, "wrong.png", testPhoto)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp uploadEvidenceResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+
+		otherURL := fmt.Sprintf("/students/%s/achievements/%s/evidence/%s", student.ID(), givenAchievement(t, achievementStore), resp.ID)
+		rr = doRequest(t, r, sessions, student, http.MethodDelete, otherURL, "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		rr = doRequest(t, r, sessions, other, http.MethodDelete, evidenceURL+"/"+resp.ID, "")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = doRequest(t, r, sessions, student, http.MethodDelete, evidenceURL+"/"+resp.ID, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		rr = doRequest(t, r, sessions, student, http.MethodGet, evidenceURL+"/"+resp.ID, "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		rr = doRequest(t, r, sessions, student, http.MethodDelete, evidenceURL+"/"+resp.ID, "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodGet, evidenceURL, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var list evidenceListResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&list))
+		require.Len(t, list.Evidence, 1)
+		assert.Equal(t, evidenceID, list.Evidence[0].ID)
+	})
+
+	t.Run("should keep evidence when the achievement is archived", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodDelete, "/achievements/"+achievementID, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodGet, evidenceURL+"/"+evidenceID, "")
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, testPhoto, rr.Body.Bytes())
+	})
+}
+
+// uploadFile posts contents as the "file" field of a multipart form.
+func uploadFile(t *testing.T, r http.Handler, sessions *session.Manager, acc account.Account, url, fileName string, contents []byte) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+	_, err = fw.Write(contents)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+	req, err := http.NewRequest(http.MethodPost, url, &body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	authorise(t, req, sessions, acc)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
@@ -0,0 +1,70 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/go-chi/chi/v5"
+)
+
+type progressEventResponse struct {
+	From    achievements.Progress `json:"from"`
+	To      achievements.Progress `json:"to"`
+	Actor   simpleAccount         `json:"actor"`
+	Time    time.Time             `json:"time"`
+	Comment string                `json:"comment,omitempty"`
+}
+
+type historyResponse struct {
+	History []progressEventResponse `json:"history"`
+}
+
+// getProgressHistory lists every change made to a student's progress on
+// an achievement, oldest first, with who made it.
+func getProgressHistory(accountStore account.Store, achievementStore achievements.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+		if err := requireStudent(req.Context(), accountStore, id); err != nil {
+			writeError(w, err)
+			return
+		}
+		achievementID := chi.URLParam(req, "achievement")
+		// Archived achievements still have a history.
+		if _, err := achievementStore.GetAchievement(req.Context(), achievementID); err != nil {
+			writeError(w, err)
+			return
+		}
+		events, err := achievementStore.GetProgressHistory(req.Context(), id, achievementID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		history := make([]progressEventResponse, len(events))
+		actors := make(map[string]simpleAccount)
+		for i, e := range events {
+			actor, ok := actors[e.ActorID]
+			if !ok {
+				actor = simpleAccount{ID: e.ActorID}
+				if acc, err := accountStore.GetAccount(req.Context(), e.ActorID); err == nil {
+					actor.Name = acc.Name()
+				}
+				actors[e.ActorID] = actor
+			}
+			history[i] = progressEventResponse{
+				From:    e.From,
+				To:      e.To,
+				Actor:   actor,
+				Time:    e.Time,
+				Comment: e.Comment,
+			}
+		}
+		err = json.NewEncoder(w).Encode(historyResponse{History: history})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
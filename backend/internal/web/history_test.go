@@ -0,0 +1,61 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressHistory(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	achievementStore := store.NewInMemory()
+	student := givenAccount(t, accountStore, account.NewStudent("Test Login"))
+	other := givenAccount(t, accountStore, account.NewStudent("Other Student"))
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroom.NewInMemoryStore(), newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
+	achievementID := givenAchievement(t, achievementStore)
+	progressURL := fmt.Sprintf("/students/%s/achievements/%s/progress", student.ID(), achievementID)
+	historyURL := fmt.Sprintf("/students/%s/achievements/%s/history", student.ID(), achievementID)
+
+	rr := doRequest(t, r, sessions, student, http.MethodPut, progressURL, `{"progress": "STARTED"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+	rr = doRequest(t, r, sessions, teacher, http.MethodPut, progressURL, `{"progress": "FINISHED"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	t.Run("should return every change with who made it", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, student, http.MethodGet, historyURL, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp historyResponse
+		err := json.NewDecoder(rr.Body).Decode(&resp)
+		require.NoError(t, err)
+		require.Len(t, resp.History, 2)
+		assert.Equal(t, achievements.NotStarted, resp.History[0].From)
+		assert.Equal(t, achievements.Started, resp.History[0].To)
+		assert.Equal(t, simpleAccount{ID: student.ID(), Name: student.Name()}, resp.History[0].Actor)
+		assert.Equal(t, achievements.Started, resp.History[1].From)
+		assert.Equal(t, achievements.Finished, resp.History[1].To)
+		assert.Equal(t, simpleAccount{ID: teacher.ID(), Name: teacher.Name()}, resp.History[1].Actor)
+		assert.False(t, resp.History[1].Time.Before(resp.History[0].Time))
+	})
+
+	t.Run("should return forbidden for another student's history", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, other, http.MethodGet, historyURL, "")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should return not found for unknown students or achievements", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodGet, "/students/not-a-student/achievements/"+achievementID+"/history", "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodGet, "/students/"+student.ID()+"/achievements/not-an-achievement/history", "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
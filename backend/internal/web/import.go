@@ -0,0 +1,48 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/roster"
+)
+
+// maxImportSize comfortably fits roster.MaxStudents names and classes.
+const maxImportSize = 1 << 20
+
+// importStudents creates a student account for every row of the CSV in
+// the request body, adding them to the teacher's class named in the
+// row, which is created if the teacher has none by that name. The
+// login codes are sent back as a CSV.
+func importStudents(accountStore account.Store, achievementStore achievements.Store, classroomStore classroom.Store, codes *account.CodeGenerator) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		students, err := roster.Parse(http.MaxBytesReader(w, req.Body, maxImportSize))
+		if verr, ok := err.(*roster.ValidationError); ok {
+			fields := make([]fieldError, len(verr.Rows))
+			for i, r := range verr.Rows {
+				fields[i] = fieldError{Row: r.Row, Message: r.Message}
+			}
+			writeError(w, invalidFields(fields...))
+			return
+		}
+		if err != nil {
+			writeError(w, errInvalidCSV)
+			return
+		}
+		teacher := accountFromContext(req.Context())
+		accounts, err := roster.Enrol(req.Context(), codes, accountStore, classroomStore, achievementStore, teacher.ID(), students)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="login-codes.csv"`)
+		err = roster.WriteCodes(w, students, accounts)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
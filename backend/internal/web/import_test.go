@@ -0,0 +1,84 @@
+package web
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportStudents(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	achievementStore := store.NewInMemory()
+	classroomStore := classroom.NewInMemoryStore()
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	student := givenAccount(t, accountStore, account.NewStudent("Test Student"))
+	existingClass := givenClassroom(t, classroomStore, "Class 3B", teacher.ID())
+	assigned := givenAchievement(t, achievementStore)
+	require.NoError(t, classroomStore.AssignAchievement(context.Background(), existingClass, assigned))
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroomStore, newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
+
+	t.Run("should return forbidden for students", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, student, http.MethodPost, "/students/import", "Ada Lovelace\n")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should reject the whole import with a message per invalid row", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, "/students/import", "name,class\nAda Lovelace,Class 3B\n,Class 3B\n")
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+		apiErr := decodeError(t, rr)
+		assert.Equal(t, "invalid_request", apiErr.Code)
+		assert.Equal(t, []fieldError{{Row: 3, Message: "name is empty"}}, apiErr.Fields)
+
+		students, err := accountStore.GetAccounts(context.Background(), account.RoleStudent)
+		require.NoError(t, err)
+		assert.Len(t, students, 1)
+	})
+
+	t.Run("should create the students and return their login codes", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, "/students/import", "name,class\nAda Lovelace,Class 3B\nAlan Turing,Class 4A\nGrace Hopper\n")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+		records, err := csv.NewReader(rr.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 4)
+		assert.Equal(t, []string{"name", "class", "code"}, records[0])
+
+		names := make(map[string]account.Account)
+		for _, rec := range records[1:] {
+			acc := loginWithCode(t, r, rec[2])
+			assert.Equal(t, rec[0], acc.Name)
+			assert.Equal(t, "Student", acc.Type)
+			stored, err := accountStore.GetAccount(context.Background(), acc.ID)
+			require.NoError(t, err)
+			names[rec[0]] = stored
+		}
+
+		c, err := classroomStore.GetClassroom(context.Background(), existingClass)
+		require.NoError(t, err)
+		assert.Equal(t, []string{names["Ada Lovelace"].ID()}, c.StudentIDs)
+		achvs, err := achievementStore.GetStudentAchievements(context.Background(), names["Ada Lovelace"].ID())
+		require.NoError(t, err)
+		require.Len(t, achvs, 1)
+		assert.Equal(t, assigned, achvs[0].AchievementID)
+
+		classes, err := classroomStore.GetTeacherClassrooms(context.Background(), teacher.ID())
+		require.NoError(t, err)
+		var created *classroom.Classroom
+		for _, c := range classes {
+			if c.Name == "Class 4A" {
+				c := c
+				created = &c
+			}
+		}
+		require.NotNil(t, created, "a class missing from the teacher's classes should be created")
+		assert.Equal(t, []string{names["Alan Turing"].ID()}, created.StudentIDs)
+	})
+}
@@ -0,0 +1,123 @@
+package web
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+)
+
+// maxPageSize is the largest limit a listing can be asked for.
+const maxPageSize = 100
+
+// listOptions reads how to filter, sort and page a listing of
+// achievements from the query of req:
+//
+//	search     part of the name, in any case
+//	category   the exact category
+//	status     active, archived or all, defaulting to status
+//	dueFrom    RFC 3339 time the due date is at or after
+//	dueBefore  RFC 3339 time the due date is before
+//	sort       created (the default), name or dueDate
+//	order      asc (the default) or desc
+//	limit      how many to return, all of them when left out
+//	cursor     where to carry on from, taken from a next link
+//
+// The returned options list one more than the limit, so the handler can
+// tell whether to link to a next page of pageSize.
+func listOptions(req *http.Request, status achievements.Status) (opts achievements.ListOptions, pageSize int, err error) {
+	query := req.URL.Query()
+	opts.Filter.Name = query.Get("search")
+	opts.Filter.Category = query.Get("category")
+	switch query.Get("status") {
+	case "":
+		opts.Filter.Status = status
+	case "active":
+		opts.Filter.Status = achievements.StatusActive
+	case "archived":
+		opts.Filter.Status = achievements.StatusArchived
+	case "all":
+		opts.Filter.Status = achievements.StatusAny
+	default:
+		return opts, 0, invalidField("status", "must be one of active, archived or all")
+	}
+	if opts.Filter.DueFrom, err = timeParam(query, "dueFrom"); err != nil {
+		return opts, 0, err
+	}
+	if opts.Filter.DueBefore, err = timeParam(query, "dueBefore"); err != nil {
+		return opts, 0, err
+	}
+	if opts.Filter.DueFrom != nil && opts.Filter.DueBefore != nil && !opts.Filter.DueFrom.Before(*opts.Filter.DueBefore) {
+		return opts, 0, invalidField("dueBefore", "must be after dueFrom")
+	}
+	switch sort := achievements.Sort(query.Get("sort")); sort {
+	case "", achievements.SortCreated, achievements.SortName, achievements.SortDueDate:
+		opts.Sort = sort
+	default:
+		return opts, 0, invalidField("sort", "must be one of created, name or dueDate")
+	}
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		opts.Descending = true
+	default:
+		return opts, 0, invalidField("order", "must be asc or desc")
+	}
+	if limit := query.Get("limit"); limit != "" {
+		pageSize, err = strconv.Atoi(limit)
+		if err != nil || pageSize < 1 || pageSize > maxPageSize {
+			return opts, 0, invalidField("limit", "must be a number from 1 to "+strconv.Itoa(maxPageSize))
+		}
+		opts.Limit = pageSize + 1
+	}
+	if cursor := query.Get("cursor"); cursor != "" {
+		id, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || len(id) == 0 {
+			return opts, 0, invalidField("cursor", "must come from a next link")
+		}
+		opts.After = string(id)
+	}
+	return opts, pageSize, nil
+}
+
+// timeParam reads the query parameter name as an RFC 3339 time, or nil
+// when it is left out.
+func timeParam(query url.Values, name string) (*time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, invalidField(name, "must be an RFC 3339 time")
+	}
+	return &t, nil
+}
+
+// progressFilter reads the progress query parameters of req, each one
+// of NOT_STARTED, STARTED, SUBMITTED or FINISHED.
+func progressFilter(req *http.Request) ([]achievements.Progress, error) {
+	var progress []achievements.Progress
+	for _, p := range req.URL.Query()["progress"] {
+		switch achievements.Progress(p) {
+		case "NOT_STARTED":
+			progress = append(progress, achievements.NotStarted)
+		case achievements.Started, achievements.Submitted, achievements.Finished:
+			progress = append(progress, achievements.Progress(p))
+		default:
+			return nil, invalidField("progress", "must be one of NOT_STARTED, STARTED, SUBMITTED or FINISHED")
+		}
+	}
+	return progress, nil
+}
+
+// linkNextPage points the Link header at the page carrying on after the
+// achievement with lastID, keeping the rest of the query of req.
+func linkNextPage(w http.ResponseWriter, req *http.Request, lastID string) {
+	query := req.URL.Query()
+	query.Set("cursor", base64.RawURLEncoding.EncodeToString([]byte(lastID)))
+	w.Header().Set("Link", `<`+req.URL.Path+`?`+query.Encode()+`>; rel="next"`)
+}
@@ -0,0 +1,233 @@
+package web
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListingAchievements(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	achievementStore := store.NewInMemory()
+	student := givenAccount(t, accountStore, account.NewStudent("Test Student"))
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	for _, name := range []string{"Bake Bread", "Adopt a Pet", "Climb a Hill"} {
+		id, err := achievementStore.CreateAchievement(context.Background(), achievements.Achievement{Name: name})
+		require.NoError(t, err)
+		require.NoError(t, achievementStore.AddProgression(context.Background(), achievements.StudentAchievement{
+			AchievementID: id,
+			StudentID:     student.ID(),
+			Progress:      achievements.Started,
+		}, teacher.ID()))
+	}
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroom.NewInMemoryStore(), newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
+
+	t.Run("should page through achievements by following next links", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, student, http.MethodGet, "/achievements?sort=name&limit=2", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		next := nextLink(t, rr)
+		assert.Equal(t, []string{"Adopt a Pet", "Bake Bread"}, listedNames(t, rr))
+
+		rr = doRequest(t, r, sessions, student, http.MethodGet, next, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("Link"), "the last page should not link to another")
+		assert.Equal(t, []string{"Climb a Hill"}, listedNames(t, rr))
+	})
+
+	t.Run("should list every achievement without a limit", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, student, http.MethodGet, "/achievements?sort=name&order=desc", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("Link"))
+		assert.Equal(t, []string{"Climb a Hill", "Bake Bread", "Adopt a Pet"}, listedNames(t, rr))
+	})
+
+	t.Run("should page through a student's achievements", func(t *testing.T) {
+		url := "/students/" + student.ID() + "/achievements?sort=name&order=desc&limit=2"
+		rr := doRequest(t, r, sessions, teacher, http.MethodGet, url, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		next := nextLink(t, rr)
+		assert.Equal(t, []string{"Climb a Hill", "Bake Bread"}, progressNames(t, rr))
+
+		rr = doRequest(t, r, sessions, teacher, http.MethodGet, next, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("Link"))
+		assert.Equal(t, []string{"Adopt a Pet"}, progressNames(t, rr))
+	})
+
+	t.Run("should point out invalid listing options", func(t *testing.T) {
+		unknown := base64.RawURLEncoding.EncodeToString([]byte("not-an-achievement"))
+		tests := []struct {
+			query string
+			field string
+		}{
+			{"sort=points", "sort"},
+			{"order=up", "order"},
+			{"limit=0", "limit"},
+			{"limit=101", "limit"},
+			{"limit=ten", "limit"},
+			{"cursor=not%20base64", "cursor"},
+			{"cursor=" + unknown, "cursor"},
+		}
+		for _, test := range tests {
+			for _, path := range []string{"/achievements", "/students/" + student.ID() + "/achievements"} {
+				rr := doRequest(t, r, sessions, student, http.MethodGet, path+"?"+test.query, "")
+				require.Equal(t, http.StatusBadRequest, rr.Code, path+"?"+test.query)
+				apiErr := decodeError(t, rr)
+				require.Len(t, apiErr.Fields, 1)
+				assert.Equal(t, test.field, apiErr.Fields[0].Field, path+"?"+test.query)
+			}
+		}
+	})
+}
+
+var nextLinkPattern = regexp.MustCompile(`^<([^>]+)>; rel="next"$`)
+
+// nextLink returns the URL the Link header of rr gives for the next
+// page, failing the test if there is none.
+func nextLink(t *testing.T, rr *httptest.ResponseRecorder) string {
+	t.Helper()
+	match := nextLinkPattern.FindStringSubmatch(rr.Header().Get("Link"))
+	require.NotNil(t, match, "expected a next link")
+	return match[1]
+}
+
+func listedNames(t *testing.T, rr *httptest.ResponseRecorder) []string {
+	var resp allAchievementsResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	names := make([]string, len(resp.Achievements))
+	for i, a := range resp.Achievements {
+		names[i] = a.Name
+	}
+	return names
+}
+
+func progressNames(t *testing.T, rr *httptest.ResponseRecorder) []string {
+	var resp achievementResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	names := make([]string, len(resp.Achievements))
+	for i, a := range resp.Achievements {
+		names[i] = a.Achievement.Name
+	}
+	return names
+}
+
+func TestFilteringAchievements(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	achievementStore := store.NewInMemory()
+	student := givenAccount(t, accountStore, account.NewStudent("Test Student"))
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroom.NewInMemoryStore(), newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
+	ids := make(map[string]string)
+	for _, body := range []string{
+		`{"name": "Bake Bread", "category": "Cooking", "dueDate": "2022-02-01T00:00:00Z"}`,
+		`{"name": "Bake a Cake", "category": " Cooking "}`,
+		`{"name": "Climb a Hill", "category": "Outdoors", "dueDate": "2022-03-01T00:00:00Z"}`,
+		`{"name": "Dig a Pond", "category": "Outdoors", "dueDate": "2022-02-15T00:00:00Z"}`,
+		`{"name": "Fly a Kite"}`,
+	} {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, "/achievements", body)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp createAchievementResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		var req createAchievementRequest
+		require.NoError(t, json.Unmarshal([]byte(body), &req))
+		ids[req.Name] = resp.ID
+	}
+	rr := doRequest(t, r, sessions, teacher, http.MethodDelete, "/achievements/"+ids["Dig a Pond"], "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	for name, progress := range map[string]achievements.Progress{
+		"Bake Bread":   achievements.Started,
+		"Bake a Cake":  achievements.NotStarted,
+		"Climb a Hill": achievements.Finished,
+	} {
+		require.NoError(t, achievementStore.AddProgression(context.Background(), achievements.StudentAchievement{
+			AchievementID: ids[name],
+			StudentID:     student.ID(),
+			Progress:      progress,
+		}, teacher.ID()))
+	}
+
+	t.Run("should search and filter achievements", func(t *testing.T) {
+		tests := []struct {
+			query string
+			want  []string
+		}{
+			{"search=bake", []string{"Bake Bread", "Bake a Cake"}},
+			{"category=Outdoors", []string{"Climb a Hill"}},
+			{"category=Outdoors&status=all", []string{"Climb a Hill", "Dig a Pond"}},
+			{"status=archived", []string{"Dig a Pond"}},
+			{"dueFrom=2022-02-01T00:00:00Z&dueBefore=2022-03-01T00:00:00Z&status=all", []string{"Bake Bread", "Dig a Pond"}},
+			{"search=a&category=Cooking&dueFrom=2022-01-01T00:00:00Z", []string{"Bake Bread"}},
+		}
+		for _, test := range tests {
+			rr := doRequest(t, r, sessions, teacher, http.MethodGet, "/achievements?sort=name&"+test.query, "")
+			require.Equal(t, http.StatusOK, rr.Code, test.query)
+			assert.Equal(t, test.want, listedNames(t, rr), test.query)
+		}
+	})
+
+	t.Run("should let students filter their achievements by progress", func(t *testing.T) {
+		url := "/students/" + student.ID() + "/achievements?sort=name&progress=NOT_STARTED&progress=FINISHED"
+		rr := doRequest(t, r, sessions, student, http.MethodGet, url, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, []string{"Bake a Cake", "Climb a Hill", "Fly a Kite"}, progressNames(t, rr))
+
+		url = "/students/" + student.ID() + "/achievements?search=BAKE&progress=STARTED"
+		rr = doRequest(t, r, sessions, student, http.MethodGet, url, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, []string{"Bake Bread"}, progressNames(t, rr))
+	})
+
+	t.Run("should keep filtering on the next page", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodGet, "/achievements?category=Cooking&sort=name&limit=1", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		next := nextLink(t, rr)
+		assert.Equal(t, []string{"Bake Bread"}, listedNames(t, rr))
+
+		rr = doRequest(t, r, sessions, teacher, http.MethodGet, next, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("Link"))
+		assert.Equal(t, []string{"Bake a Cake"}, listedNames(t, rr))
+	})
+
+	t.Run("should move an achievement to another category", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPut, "/achievements/"+ids["Bake a Cake"], `{"name": "Bake a Cake", "category": "Baking"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodGet, "/achievements?category=Baking", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, []string{"Bake a Cake"}, listedNames(t, rr))
+	})
+
+	t.Run("should point out invalid filters", func(t *testing.T) {
+		tests := []struct {
+			query string
+			field string
+		}{
+			{"status=gone", "status"},
+			{"dueFrom=yesterday", "dueFrom"},
+			{"dueBefore=2022-02-30", "dueBefore"},
+			{"dueFrom=2022-03-01T00:00:00Z&dueBefore=2022-02-01T00:00:00Z", "dueBefore"},
+			{"progress=DONE", "progress"},
+		}
+		for _, test := range tests {
+			rr := doRequest(t, r, sessions, student, http.MethodGet, "/students/"+student.ID()+"/achievements?"+test.query, "")
+			require.Equal(t, http.StatusBadRequest, rr.Code, test.query)
+			apiErr := decodeError(t, rr)
+			require.Len(t, apiErr.Fields, 1)
+			assert.Equal(t, test.field, apiErr.Fields[0].Field, test.query)
+		}
+	})
+}
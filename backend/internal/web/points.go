@@ -0,0 +1,199 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/go-chi/chi/v5"
+)
+
+type pointsRequest struct {
+	Points int `json:"points"`
+}
+
+func setAchievementPoints(store achievements.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		achievementID := chi.URLParam(req, "achievement")
+		if err := requireAchievement(req.Context(), store, achievementID); err != nil {
+			writeError(w, err)
+			return
+		}
+		var pointsReq pointsRequest
+		err := json.NewDecoder(req.Body).Decode(&pointsReq)
+		if err != nil {
+			writeError(w, errInvalidJSON)
+			return
+		}
+		if pointsReq.Points < 0 {
+			writeError(w, invalidField("points", "must not be negative"))
+			return
+		}
+		err = store.SetPoints(req.Context(), achievementID, pointsReq.Points)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+type studentPointsResponse struct {
+	Balance int                        `json:"balance"`
+	Ledger  []achievements.PointsEntry `json:"ledger"`
+}
+
+func getStudentPoints(accountStore account.Store, achievementStore achievements.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+		if err := requireStudent(req.Context(), accountStore, id); err != nil {
+			writeError(w, err)
+			return
+		}
+		ledger, err := achievementStore.GetPointsLedger(req.Context(), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		resp := studentPointsResponse{Ledger: ledger}
+		for _, e := range ledger {
+			resp.Balance += e.Points
+		}
+		err = json.NewEncoder(w).Encode(resp)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+type leaderboardEntry struct {
+	Student simpleAccount `json:"student"`
+	Points  int           `json:"points"`
+}
+
+type leaderboardResponse struct {
+	Leaderboard []leaderboardEntry `json:"leaderboard"`
+}
+
+// getClassroomLeaderboard ranks the students of a class by their points
+// balance, highest first.
+func getClassroomLeaderboard(accountStore account.Store, achievementStore achievements.Store, classroomStore classroom.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		c, err := teacherClassroom(req, classroomStore)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		balances, err := achievementStore.GetPointsBalances(req.Context(), c.StudentIDs)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		leaderboard := make([]leaderboardEntry, 0, len(c.StudentIDs))
+		for _, id := range c.StudentIDs {
+			student, err := accountStore.GetAccount(req.Context(), id)
+			if errors.Is(err, account.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			leaderboard = append(leaderboard, leaderboardEntry{
+				Student: simpleAccount{ID: student.ID(), Name: student.Name()},
+				Points:  balances[id],
+			})
+		}
+		sort.SliceStable(leaderboard, func(i, j int) bool {
+			if leaderboard[i].Points != leaderboard[j].Points {
+				return leaderboard[i].Points > leaderboard[j].Points
+			}
+			return leaderboard[i].Student.Name < leaderboard[j].Student.Name
+		})
+		err = json.NewEncoder(w).Encode(leaderboardResponse{Leaderboard: leaderboard})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+type createRewardRequest struct {
+	Name string `json:"name"`
+	Cost int    `json:"cost"`
+}
+
+type createRewardResponse struct {
+	ID string `json:"id"`
+}
+
+func createReward(store achievements.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var rewardReq createRewardRequest
+		err := json.NewDecoder(req.Body).Decode(&rewardReq)
+		if err != nil {
+			writeError(w, errInvalidJSON)
+			return
+		}
+		var fields []fieldError
+		if strings.TrimSpace(rewardReq.Name) == "" {
+			fields = append(fields, fieldError{Field: "name", Message: "is required"})
+		}
+		if rewardReq.Cost < 0 {
+			fields = append(fields, fieldError{Field: "cost", Message: "must not be negative"})
+		}
+		if len(fields) > 0 {
+			writeError(w, invalidFields(fields...))
+			return
+		}
+		id, err := store.CreateReward(req.Context(), rewardReq.Name, rewardReq.Cost)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		err = json.NewEncoder(w).Encode(createRewardResponse{ID: id})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+type allRewardsResponse struct {
+	Rewards []achievements.Reward `json:"rewards"`
+}
+
+func getAllRewards(store achievements.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		rewards, err := store.GetAllRewards(req.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		err = json.NewEncoder(w).Encode(allRewardsResponse{Rewards: rewards})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+func redeemReward(accountStore account.Store, achievementStore achievements.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+		if err := requireStudent(req.Context(), accountStore, id); err != nil {
+			writeError(w, err)
+			return
+		}
+		err := achievementStore.RedeemReward(req.Context(), id, chi.URLParam(req, "reward"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
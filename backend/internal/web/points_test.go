@@ -0,0 +1,145 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPointsAndRewards(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	achievementStore := store.NewInMemory()
+	classroomStore := classroom.NewInMemoryStore()
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	alice := givenAccount(t, accountStore, account.NewStudent("Alice"))
+	bob := givenAccount(t, accountStore, account.NewStudent("Bob"))
+	classID := givenClassroom(t, classroomStore, "Class 3B", teacher.ID())
+	require.NoError(t, classroomStore.AddStudent(context.Background(), classID, alice.ID()))
+	require.NoError(t, classroomStore.AddStudent(context.Background(), classID, bob.ID()))
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroomStore, newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
+
+	var achievementID string
+	t.Run("should create an achievement worth points", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, "/achievements", `{"name": "Recycle 10 Batteries", "points": 15}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp createAchievementResponse
+		err := json.NewDecoder(rr.Body).Decode(&resp)
+		require.NoError(t, err)
+		achievementID = resp.ID
+		a, err := achievementStore.GetAchievement(context.Background(), achievementID)
+		require.NoError(t, err)
+		assert.Equal(t, 15, a.Points)
+	})
+
+	t.Run("should return bad request for negative points", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, "/achievements", `{"name": "Recycle 10 Batteries", "points": -1}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodPut, "/achievements/"+achievementID+"/points", `{"points": -1}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should let only teachers change the points of an achievement", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, alice, http.MethodPut, "/achievements/"+achievementID+"/points", `{"points": 100}`)
+		require.Equal(t, http.StatusForbidden, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodPut, "/achievements/"+achievementID+"/points", `{"points": 20}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodPut, "/achievements/not-an-achievement/points", `{"points": 20}`)
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should credit a student who finishes an achievement", func(t *testing.T) {
+		url := fmt.Sprintf("/students/%s/achievements/%s/progress", alice.ID(), achievementID)
+		rr := doRequest(t, r, sessions, alice, http.MethodPut, url, `{"progress": "SUBMITTED"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodPost, fmt.Sprintf("/students/%s/achievements/%s/approve", alice.ID(), achievementID), "")
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		rr = doRequest(t, r, sessions, alice, http.MethodGet, "/students/"+alice.ID()+"/points", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp studentPointsResponse
+		err := json.NewDecoder(rr.Body).Decode(&resp)
+		require.NoError(t, err)
+		assert.Equal(t, 20, resp.Balance)
+		require.Len(t, resp.Ledger, 1)
+		assert.Equal(t, achievements.ReasonAchievementFinished, resp.Ledger[0].Reason)
+	})
+
+	t.Run("should not show a student another student's points", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, bob, http.MethodGet, "/students/"+alice.ID()+"/points", "")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should rank a class by points", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodGet, "/classes/"+classID+"/leaderboard", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp leaderboardResponse
+		err := json.NewDecoder(rr.Body).Decode(&resp)
+		require.NoError(t, err)
+		assert.Equal(t, []leaderboardEntry{
+			{Student: simpleAccount{ID: alice.ID(), Name: "Alice"}, Points: 20},
+			{Student: simpleAccount{ID: bob.ID(), Name: "Bob"}, Points: 0},
+		}, resp.Leaderboard)
+
+		rr = doRequest(t, r, sessions, teacher, http.MethodGet, "/classes/not-a-class/leaderboard", "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	var rewardID string
+	t.Run("should let only teachers create rewards", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, alice, http.MethodPost, "/rewards", `{"name": "Extra Playtime", "cost": 1}`)
+		require.Equal(t, http.StatusForbidden, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodPost, "/rewards", `{"name": "", "cost": 1}`)
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+
+		rr = doRequest(t, r, sessions, teacher, http.MethodPost, "/rewards", `{"name": "Extra Playtime", "cost": 15}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp createRewardResponse
+		err := json.NewDecoder(rr.Body).Decode(&resp)
+		require.NoError(t, err)
+		rewardID = resp.ID
+
+		rr = doRequest(t, r, sessions, alice, http.MethodGet, "/rewards", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var all allRewardsResponse
+		err = json.NewDecoder(rr.Body).Decode(&all)
+		require.NoError(t, err)
+		assert.Equal(t, []achievements.Reward{{ID: rewardID, Name: "Extra Playtime", Cost: 15}}, all.Rewards)
+	})
+
+	t.Run("should redeem a reward the student can afford", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, alice, http.MethodPost, "/students/"+alice.ID()+"/rewards/"+rewardID, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		balances, err := achievementStore.GetPointsBalances(context.Background(), []string{alice.ID()})
+		require.NoError(t, err)
+		assert.Equal(t, 5, balances[alice.ID()])
+	})
+
+	t.Run("should return conflict when the student cannot afford a reward", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, alice, http.MethodPost, "/students/"+alice.ID()+"/rewards/"+rewardID, "")
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("should return not found for unknown rewards", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, alice, http.MethodPost, "/students/"+alice.ID()+"/rewards/not-a-reward", "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should reverse the credit when a finished achievement is reopened", func(t *testing.T) {
+		url := fmt.Sprintf("/students/%s/achievements/%s/progress", alice.ID(), achievementID)
+		rr := doRequest(t, r, sessions, teacher, http.MethodPut, url, `{"progress": "STARTED"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		balances, err := achievementStore.GetPointsBalances(context.Background(), []string{alice.ID()})
+		require.NoError(t, err)
+		assert.Equal(t, -15, balances[alice.ID()])
+	})
+}
@@ -1,31 +1,45 @@
 package web
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"github.com/Manchester-Dev/medlock/internal/account"
 	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/evidence"
+	"github.com/Manchester-Dev/medlock/internal/session"
+	"github.com/Manchester-Dev/medlock/internal/throttle"
 	"github.com/go-chi/cors"
+	"math"
+	"net"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type loginRequest struct {
 	Code string `json:"code"`
+	// Password is only needed for teachers who have set one.
+	Password string `json:"password,omitempty"`
 }
 
 type loginResponse struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	Type string `json:"type"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Token string `json:"token"`
 }
 
 func toType(role string) string {
 	switch role {
-	case "teacher":
+	case account.RoleTeacher:
 		return "Teacher"
-	case "student":
+	case account.RoleStudent:
 		return "Student"
 	default:
 		return ""
@@ -40,34 +54,96 @@ type simpleAchievement struct {
 type progressResponse struct {
 	Achievement simpleAchievement     `json:"achievement"`
 	Progress    achievements.Progress `json:"progress"`
+	DueDate     *time.Time            `json:"dueDate,omitempty"`
+	Overdue     bool                  `json:"overdue"`
+	Archived    bool                  `json:"archived,omitempty"`
 }
 
 type achievementResponse struct {
 	Achievements []progressResponse `json:"achievements"`
 }
 
-func NewRouter(accountStore account.Store, achievementStore achievements.Store) http.Handler {
+func NewRouter(accountStore account.Store, achievementStore achievements.Store, classroomStore classroom.Store, locker *evidence.Locker, codes *account.CodeGenerator, guard *throttle.Guard, sessions *session.Manager) http.Handler {
 	router := chi.NewRouter()
 
 	router.Use(cors.Handler(cors.Options{
 		// AllowedOrigins:   []string{"https://foo.com"}, // Use this to allow specific origin hosts
 		AllowedOrigins: []string{"https://*", "http://localhost*"},
 		// AllowOriginFunc:  func(r *http.Request, origin string) bool { return true },
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: false,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}))
+	router.NotFound(func(w http.ResponseWriter, req *http.Request) {
+		writeError(w, errRouteNotFound)
+	})
+	router.MethodNotAllowed(func(w http.ResponseWriter, req *http.Request) {
+		writeError(w, errMethodNotAllowed)
+	})
 
 	router.Get("/health", func(writer http.ResponseWriter, req *http.Request) {
 		writer.WriteHeader(http.StatusOK)
 	})
-	router.Get("/students/{id}/achievements", getStudentAchievements(accountStore, achievementStore))
-	router.Put("/students/{id}/achievements/{achievement}/progress", updateAchievementProgress(accountStore, achievementStore))
-	router.Post("/achievements", createAchievement(achievementStore))
-	router.Get("/achievements", getAllAchievements(achievementStore))
-	router.Post("/login", login(accountStore))
+	router.Post("/login", login(accountStore, guard, sessions))
+
+	router.Group(func(router chi.Router) {
+		router.Use(authenticate(accountStore, sessions))
+		router.With(onlyTeachers).Get("/login/attempts", getLoginAttempts(accountStore, guard))
+		router.With(onlyTeachers).Post("/students", createAccount(accountStore, codes, account.RoleStudent))
+		router.With(onlyTeachers).Get("/students", getStudents(accountStore))
+		router.With(onlyTeachers).Post("/students/import", importStudents(accountStore, achievementStore, classroomStore, codes))
+		router.Route("/students/{id}", func(router chi.Router) {
+			router.Use(onlySelfOrTeacher)
+			router.With(onlyTeachers).Patch("/", renameAccount(accountStore, account.RoleStudent))
+			router.With(onlyTeachers).Delete("/", deleteAccount(accountStore, locker, account.RoleStudent))
+			router.With(onlyTeachers).Post("/code", regenerateCode(accountStore, codes, account.RoleStudent))
+			router.Get("/achievements", getStudentAchievements(accountStore, achievementStore))
+			router.Put("/achievements/{achievement}/progress", updateAchievementProgress(accountStore, achievementStore))
+			router.Get("/achievements/{achievement}/history", getProgressHistory(accountStore, achievementStore))
+			router.With(onlyTeachers).Post("/achievements/{achievement}/approve", reviewSubmission(accountStore, achievementStore, true))
+			router.With(onlyTeachers).Post("/achievements/{achievement}/reject", reviewSubmission(accountStore, achievementStore, false))
+			router.Post("/achievements/{achievement}/evidence", uploadEvidence(accountStore, achievementStore, locker))
+			router.Get("/achievements/{achievement}/evidence", listEvidence(accountStore, locker))
+			router.Get("/achievements/{achievement}/evidence/{evidence}", downloadEvidence(locker))
+			router.Delete("/achievements/{achievement}/evidence/{evidence}", deleteEvidence(locker))
+			router.Get("/points", getStudentPoints(accountStore, achievementStore))
+			router.Post("/rewards/{reward}", redeemReward(accountStore, achievementStore))
+		})
+		router.With(onlyTeachers).Get("/codes/usage", getCodeUsage(accountStore, codes))
+		router.Route("/teachers", func(router chi.Router) {
+			router.Use(onlyTeachers)
+			router.Post("/", createAccount(accountStore, codes, account.RoleTeacher))
+			router.Patch("/{id}", renameAccount(accountStore, account.RoleTeacher))
+			router.Delete("/{id}", deleteAccount(accountStore, locker, account.RoleTeacher))
+			router.With(onlySelf).Post("/{id}/code", regenerateCode(accountStore, codes, account.RoleTeacher))
+			router.With(onlySelf).Put("/{id}/password", setPassword(accountStore, sessions))
+			router.With(onlySelf).Delete("/{id}/password", removePassword(accountStore, sessions))
+		})
+		router.With(onlyTeachers).Post("/achievements", createAchievement(achievementStore))
+		router.Get("/achievements", getAllAchievements(achievementStore))
+		router.With(onlyTeachers).Get("/achievements/overdue", getOverdueAchievements(accountStore, achievementStore, classroomStore))
+		router.With(onlyTeachers).Put("/achievements/{achievement}", updateAchievement(achievementStore))
+		router.With(onlyTeachers).Delete("/achievements/{achievement}", archiveAchievement(achievementStore))
+		router.With(onlyTeachers).Put("/achievements/{achievement}/due-date", setAchievementDueDate(achievementStore))
+		router.With(onlyTeachers).Put("/achievements/{achievement}/points", setAchievementPoints(achievementStore))
+		router.With(onlyTeachers).Post("/rewards", createReward(achievementStore))
+		router.Get("/rewards", getAllRewards(achievementStore))
+		router.With(onlyTeachers).Get("/dashboard", getDashboard(accountStore, achievementStore, classroomStore))
+		router.With(onlyTeachers).Get("/submissions", getPendingSubmissions(accountStore, achievementStore, classroomStore))
+		router.Route("/classes", func(router chi.Router) {
+			router.Use(onlyTeachers)
+			router.Post("/", createClassroom(classroomStore))
+			router.Get("/", getTeacherClassrooms(classroomStore))
+			router.Get("/{class}/students", getClassroomStudents(accountStore, classroomStore))
+			router.Get("/{class}/cards", getLoginCards(accountStore, classroomStore))
+			router.Put("/{class}/students/{student}", addClassroomStudent(accountStore, achievementStore, classroomStore))
+			router.Delete("/{class}/students/{student}", removeClassroomStudent(classroomStore))
+			router.Put("/{class}/achievements/{achievement}", assignClassroomAchievement(achievementStore, classroomStore))
+			router.Get("/{class}/leaderboard", getClassroomLeaderboard(accountStore, achievementStore, classroomStore))
+		})
+	})
 
 	return router
 }
@@ -76,20 +152,39 @@ type allAchievementsResponse struct {
 	Achievements []achievements.Achievement `json:"achievements"`
 }
 
+// getAllAchievements lists achievements, only those which are not
+// archived unless asked otherwise, filtered, sorted and paged as
+// described by listOptions.
 func getAllAchievements(store achievements.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		achvs := store.GetAllAchievements()
+		opts, pageSize, err := listOptions(req, achievements.StatusActive)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		achvs, err := store.ListAchievements(req.Context(), opts)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if pageSize > 0 && len(achvs) > pageSize {
+			achvs = achvs[:pageSize]
+			linkNextPage(w, req, achvs[pageSize-1].ID)
+		}
 		resp := allAchievementsResponse{Achievements: achvs}
-		err := json.NewEncoder(w).Encode(resp)
+		err = json.NewEncoder(w).Encode(resp)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 	}
 }
 
 type createAchievementRequest struct {
-	Name string `json:"name"`
+	Name     string     `json:"name"`
+	Category string     `json:"category"`
+	DueDate  *time.Time `json:"dueDate"`
+	Points   int        `json:"points"`
 }
 
 type createAchievementResponse struct {
@@ -101,17 +196,195 @@ func createAchievement(store achievements.Store) http.HandlerFunc {
 		var achReq createAchievementRequest
 		err := json.NewDecoder(req.Body).Decode(&achReq)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
+			writeError(w, errInvalidJSON)
+			return
+		}
+		if apiErr := validateAchievement(achReq.Name, achReq.Points); apiErr != nil {
+			writeError(w, apiErr)
 			return
 		}
-		if strings.TrimSpace(achReq.Name) == "" {
-			w.WriteHeader(http.StatusBadRequest)
+		id, err := store.CreateAchievement(req.Context(), achievements.Achievement{
+			Name:     achReq.Name,
+			Category: strings.TrimSpace(achReq.Category),
+			DueDate:  achReq.DueDate,
+			Points:   achReq.Points,
+		})
+		if err != nil {
+			writeError(w, err)
 			return
 		}
-		id := store.CreateAchievement(achReq.Name)
 		err = json.NewEncoder(w).Encode(createAchievementResponse{ID: id})
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+// validateAchievement checks the details of an achievement being
+// created or updated, returning nil if they are fine.
+func validateAchievement(name string, points int) *apiError {
+	var fields []fieldError
+	if strings.TrimSpace(name) == "" {
+		fields = append(fields, fieldError{Field: "name", Message: "is required"})
+	}
+	if points < 0 {
+		fields = append(fields, fieldError{Field: "points", Message: "must not be negative"})
+	}
+	if len(fields) > 0 {
+		return invalidFields(fields...)
+	}
+	return nil
+}
+
+type updateAchievementRequest struct {
+	Name     string     `json:"name"`
+	Category string     `json:"category"`
+	DueDate  *time.Time `json:"dueDate"`
+	Points   int        `json:"points"`
+}
+
+// updateAchievement replaces the editable details of an achievement.
+// Leaving out the category or due date clears it.
+func updateAchievement(store achievements.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		achievementID := chi.URLParam(req, "achievement")
+		if err := requireAchievement(req.Context(), store, achievementID); err != nil {
+			writeError(w, err)
+			return
+		}
+		var updateReq updateAchievementRequest
+		err := json.NewDecoder(req.Body).Decode(&updateReq)
+		if err != nil {
+			writeError(w, errInvalidJSON)
+			return
+		}
+		if apiErr := validateAchievement(updateReq.Name, updateReq.Points); apiErr != nil {
+			writeError(w, apiErr)
+			return
+		}
+		err = store.UpdateAchievement(req.Context(), achievements.Achievement{
+			ID:       achievementID,
+			Name:     updateReq.Name,
+			Category: strings.TrimSpace(updateReq.Category),
+			DueDate:  updateReq.DueDate,
+			Points:   updateReq.Points,
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+// archiveAchievement removes an achievement from the list students can
+// work on. Students' progress on it and the evidence they uploaded for
+// it are kept as their history.
+func archiveAchievement(store achievements.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		err := store.ArchiveAchievement(req.Context(), chi.URLParam(req, "achievement"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+type dueDateRequest struct {
+	DueDate *time.Time `json:"dueDate"`
+}
+
+// setAchievementDueDate sets or, given a null dueDate, clears when an
+// achievement must be finished by.
+func setAchievementDueDate(store achievements.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		achievementID := chi.URLParam(req, "achievement")
+		if err := requireAchievement(req.Context(), store, achievementID); err != nil {
+			writeError(w, err)
+			return
+		}
+		var dueReq dueDateRequest
+		err := json.NewDecoder(req.Body).Decode(&dueReq)
+		if err != nil {
+			writeError(w, errInvalidJSON)
+			return
+		}
+		err = store.SetDueDate(req.Context(), achievementID, dueReq.DueDate)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+type overdueAchievement struct {
+	Student     simpleAccount         `json:"student"`
+	Achievement simpleAchievement     `json:"achievement"`
+	Progress    achievements.Progress `json:"progress"`
+	DueDate     time.Time             `json:"dueDate"`
+}
+
+type overdueResponse struct {
+	Overdue []overdueAchievement `json:"overdue"`
+}
+
+// getOverdueAchievements lists the achievements of the students in the
+// teacher's classes that are past their due date without having been
+// finished, soonest due first.
+func getOverdueAchievements(accountStore account.Store, achievementStore achievements.Store, classroomStore classroom.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		teacher := accountFromContext(req.Context())
+		studentIDs, err := teacherStudentIDs(req.Context(), classroomStore, teacher.ID())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		achvs, err := achievementStore.GetOverdueAchievements(req.Context(), time.Now())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		all, err := achievementStore.GetAllAchievements(req.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		details := make(map[string]achievements.Achievement, len(all))
+		for _, a := range all {
+			details[a.ID] = a
+		}
+		names, err := studentNames(req.Context(), accountStore)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		inClass := make(map[string]bool, len(studentIDs))
+		for _, id := range studentIDs {
+			inClass[id] = true
+		}
+		overdue := make([]overdueAchievement, 0, len(achvs))
+		for _, sa := range achvs {
+			a, ok := details[sa.AchievementID]
+			if !ok || a.DueDate == nil {
+				continue
+			}
+			name, ok := names[sa.StudentID]
+			if !ok || !inClass[sa.StudentID] {
+				continue
+			}
+			overdue = append(overdue, overdueAchievement{
+				Student:     simpleAccount{ID: sa.StudentID, Name: name},
+				Achievement: simpleAchievement{Name: a.Name, ID: a.ID},
+				Progress:    sa.Progress,
+				DueDate:     *a.DueDate,
+			})
+		}
+		sort.Slice(overdue, func(i, j int) bool {
+			return overdue[i].DueDate.Before(overdue[j].DueDate)
+		})
+		err = json.NewEncoder(w).Encode(overdueResponse{Overdue: overdue})
+		if err != nil {
+			writeError(w, err)
 			return
 		}
 	}
@@ -124,102 +397,236 @@ type progressUpdateRequest struct {
 func updateAchievementProgress(accountsStore account.Store, achievementsStore achievements.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		id := chi.URLParam(req, "id")
-		if !accountsStore.AccountExists(id) {
-			w.WriteHeader(http.StatusNotFound)
+		if err := requireStudent(req.Context(), accountsStore, id); err != nil {
+			writeError(w, err)
 			return
 		}
 		achievementID := chi.URLParam(req, "achievement")
-		if !achievementsStore.AchievementExists(achievementID) {
-			w.WriteHeader(http.StatusNotFound)
+		if err := requireAchievement(req.Context(), achievementsStore, achievementID); err != nil {
+			writeError(w, err)
 			return
 		}
 		if req.Body == nil {
-			w.WriteHeader(http.StatusBadRequest)
+			writeError(w, errInvalidJSON)
 			return
 		}
 		var progReq progressUpdateRequest
 		err := json.NewDecoder(req.Body).Decode(&progReq)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
+			writeError(w, errInvalidJSON)
 			return
 		}
 		if progReq.Progress != string(achievements.NotStarted) &&
 			progReq.Progress != string(achievements.Started) &&
+			progReq.Progress != string(achievements.Submitted) &&
 			progReq.Progress != string(achievements.Finished) {
-			w.WriteHeader(http.StatusBadRequest)
+			writeError(w, invalidField("progress", "must be one of STARTED, SUBMITTED or FINISHED, or empty"))
 			return
 		}
-		achievementsStore.AddProgression(achievements.StudentAchievement{
+		// Students submit finished work for a teacher to approve rather
+		// than finishing it themselves.
+		actor := accountFromContext(req.Context())
+		if progReq.Progress == string(achievements.Finished) && actor.Role() != account.RoleTeacher {
+			writeError(w, errForbidden)
+			return
+		}
+		err = achievementsStore.AddProgression(req.Context(), achievements.StudentAchievement{
 			AchievementID: achievementID,
 			StudentID:     id,
 			Progress:      achievements.Progress(progReq.Progress),
-		})
+		}, actor.ID())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
 	}
 }
 
+// getStudentAchievements lists a student's progress, filtered, sorted
+// and paged by the achievements it is on as described by listOptions.
+// It can also be filtered by progress, given as any number of progress
+// query parameters.
 func getStudentAchievements(accountsStore account.Store, achievementsStore achievements.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		id := chi.URLParam(req, "id")
-		if !accountsStore.AccountExists(id) {
-			w.WriteHeader(http.StatusNotFound)
+		if err := requireStudent(req.Context(), accountsStore, id); err != nil {
+			writeError(w, err)
+			return
+		}
+		opts, pageSize, err := listOptions(req, achievements.StatusAny)
+		if err != nil {
+			writeError(w, err)
 			return
 		}
-		achvs, err := achievementsStore.GetStudentAchievements(id)
+		opts.Filter.Progress, err = progressFilter(req)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
-		names := make([]string, len(achvs))
+		achvs, err := achievementsStore.ListStudentAchievements(req.Context(), id, opts)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if pageSize > 0 && len(achvs) > pageSize {
+			achvs = achvs[:pageSize]
+			linkNextPage(w, req, achvs[pageSize-1].AchievementID)
+		}
+		details := make([]achievements.Achievement, len(achvs))
 		for i, a := range achvs {
-			aa, err := achievementsStore.GetAchievement(a.AchievementID)
-			if err != nil {
+			aa, err := achievementsStore.GetAchievement(req.Context(), a.AchievementID)
+			if errors.Is(err, achievements.ErrNotFound) {
 				continue
 			}
-			names[i] = aa.Name
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			details[i] = *aa
 		}
-		err = json.NewEncoder(w).Encode(toAchievementResponse(achvs, names))
+		err = json.NewEncoder(w).Encode(toAchievementResponse(achvs, details, time.Now()))
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 	}
 }
 
-func toAchievementResponse(achvs []achievements.StudentAchievement, names []string) achievementResponse {
+func toAchievementResponse(achvs []achievements.StudentAchievement, details []achievements.Achievement, now time.Time) achievementResponse {
 	a := make([]progressResponse, len(achvs))
 	for i, aa := range achvs {
 		a[i] = progressResponse{
 			Achievement: simpleAchievement{
-				Name: names[i],
+				Name: details[i].Name,
 				ID:   aa.AchievementID,
 			},
 			Progress: aa.Progress,
+			DueDate:  details[i].DueDate,
+			Overdue:  !details[i].Archived && details[i].Overdue(aa.Progress, now),
+			Archived: details[i].Archived,
 		}
 	}
 	return achievementResponse{Achievements: a}
 }
 
-func login(accountStore account.Store) http.HandlerFunc {
+// requireStudent returns errStudentNotFound unless the account with id
+// exists and is a student's, or the error from checking.
+func requireStudent(ctx context.Context, store account.Store, id string) error {
+	acc, err := store.GetAccount(ctx, id)
+	if errors.Is(err, account.ErrNotFound) {
+		return errStudentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if acc.Role() != account.RoleStudent {
+		return errStudentNotFound
+	}
+	return nil
+}
+
+// requireAchievement returns errAchievementNotFound unless the
+// achievement with id exists and is not archived, or the error from
+// checking.
+func requireAchievement(ctx context.Context, store achievements.Store, id string) error {
+	exists, err := store.AchievementExists(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errAchievementNotFound
+	}
+	return nil
+}
+
+// clientAddress identifies the client making req by its IP address.
+// Behind a reverse proxy this is the proxy's address, so every client
+// shares one allowance of failed logins.
+func clientAddress(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// login exchanges a login code, and the password of a teacher who has
+// set one, for a session token. A wrong or missing password looks the
+// same as a code that does not exist. Clients that keep failing with a
+// code are made to wait, with 429 Too Many Requests and a Retry-After
+// header, before they may try it again.
+func login(accountStore account.Store, guard *throttle.Guard, sessions *session.Manager) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		var loginReq loginRequest
 		err := json.NewDecoder(req.Body).Decode(&loginReq)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
+			writeError(w, errInvalidJSON)
 			return
 		}
-		loggedIn, err := accountStore.Login(loginReq.Code)
-		if _, ok := err.(*account.CodeDoesNotExistError); ok {
-			w.WriteHeader(http.StatusUnauthorized)
+		attempt, wait, ok := guard.Begin(clientAddress(req), loginReq.Code)
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeError(w, errTooManyAttempts)
+			return
+		}
+		defer attempt.Done()
+		loggedIn, err := accountStore.Login(req.Context(), loginReq.Code)
+		if errors.Is(err, account.ErrNotFound) {
+			attempt.Fail()
+			writeError(w, errInvalidLogin)
 			return
 		}
-		err = json.NewEncoder(w).Encode(loginResponse{
-			ID:   loggedIn.ID(),
-			Name: loggedIn.Name(),
-			Type: toType(loggedIn.Role()),
-		})
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
+		if loggedIn.Role() == account.RoleTeacher {
+			ok, err := passwordMatches(req.Context(), accountStore, loggedIn.ID(), loginReq.Password)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			if !ok {
+				attempt.FailPassword(loggedIn.ID())
+				writeError(w, errInvalidLogin)
+				return
+			}
+		}
+		attempt.Succeed()
+		writeSession(req.Context(), w, accountStore, sessions, loggedIn)
+	}
+}
+
+// passwordMatches reports whether password is right for the account,
+// which is always the case for accounts without a password.
+func passwordMatches(ctx context.Context, accountStore account.Store, id string, password string) (bool, error) {
+	has, err := account.HasPassword(ctx, accountStore, id)
+	if err != nil || !has {
+		return !has, err
+	}
+	return account.CheckPassword(ctx, accountStore, id, password)
+}
+
+// writeSession responds with a new session token for acc.
+func writeSession(ctx context.Context, w http.ResponseWriter, accountStore account.Store, sessions *session.Manager, acc account.Account) {
+	credential, err := sessionCredential(ctx, accountStore, acc)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	token, err := sessions.Issue(acc.ID(), credential)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	err = json.NewEncoder(w).Encode(loginResponse{
+		ID:    acc.ID(),
+		Name:  acc.Name(),
+		Type:  toType(acc.Role()),
+		Token: token,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
 	}
 }
@@ -2,21 +2,27 @@ package web
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/Manchester-Dev/medlock/internal/account"
 	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/evidence"
+	"github.com/Manchester-Dev/medlock/internal/session"
 	"github.com/Manchester-Dev/medlock/internal/store"
+	"github.com/Manchester-Dev/medlock/internal/throttle"
 	gonanoid "github.com/matoous/go-nanoid/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestHealthCheck(t *testing.T) {
-	r := NewRouter(nil, nil)
+	r := NewRouter(nil, nil, nil, nil, nil, nil, nil)
 	require.NotNil(t, r)
 	req, err := http.NewRequest("GET", "/health", nil)
 	require.NoError(t, err)
@@ -37,9 +43,10 @@ func TestLogin(t *testing.T) {
 
 	accountStore := account.NewInMemoryStore()
 	student := account.NewStudent("Test Login")
-	err := accountStore.SaveAccount(student)
+	err := accountStore.SaveAccount(context.Background(), student)
 	require.NoError(t, err)
-	r := NewRouter(accountStore, nil)
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, nil, nil, nil, nil, newTestGuard(), sessions)
 	require.NotNil(t, r)
 
 	t.Run("should return id and name of stored account", func(t *testing.T) {
@@ -56,6 +63,21 @@ func TestLogin(t *testing.T) {
 		assert.Equal(t, student.Name(), resp.Name)
 	})
 
+	t.Run("should return a session token for the stored account", func(t *testing.T) {
+		loginReq := []byte(fmt.Sprintf(`{"code": "%s"}`, student.Code()))
+		req, err := http.NewRequest("POST", "/login", bytes.NewBuffer(loginReq))
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp loginResponse
+		err = json.NewDecoder(rr.Body).Decode(&resp)
+		require.NoError(t, err)
+		id, err := sessions.Verify(resp.Token)
+		require.NoError(t, err)
+		assert.Equal(t, student.ID(), id)
+	})
+
 	t.Run("should return unauthorised if account with code is not stored", func(t *testing.T) {
 		notStoredAccount := account.NewStudent("Don't Store Me")
 		loginReq := loginRequest{Code: notStoredAccount.Code()}
@@ -79,32 +101,84 @@ func TestLogin(t *testing.T) {
 
 }
 
+func TestLoginThrottling(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	student := givenAccount(t, accountStore, account.NewStudent("Test Login"))
+	r := NewRouter(accountStore, nil, nil, nil, nil, newTestGuard(), newTestSessions())
+
+	for i := 0; i < throttle.DefaultPolicy.Free; i++ {
+		rr := doLogin(t, r, "XXXXXX")
+		require.Equal(t, http.StatusUnauthorized, rr.Code)
+	}
+	rr := doLogin(t, r, "XXXXXX")
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	t.Run("should lock out the client after repeated failures", func(t *testing.T) {
+		rr := doLogin(t, r, "XXXXXX")
+		require.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+	})
+
+	t.Run("should not lock out other codes from the same client", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, doLogin(t, r, student.Code()).Code)
+	})
+
+	t.Run("should not lock out other clients", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"code": "XXXXXX"}`))
+		require.NoError(t, err)
+		req.RemoteAddr = "198.51.100.7:4321"
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
 func TestGetAchievementsForStudent(t *testing.T) {
 	accountStore := account.NewInMemoryStore()
 	achievementStore := store.NewInMemory()
 	student := account.NewStudent("Test Login")
-	err := accountStore.SaveAccount(student)
+	err := accountStore.SaveAccount(context.Background(), student)
+	require.NoError(t, err)
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	aID, err := achievementStore.CreateAchievement(context.Background(), achievements.Achievement{Name: "achievement"})
 	require.NoError(t, err)
-	aID := achievementStore.CreateAchievement("achievement")
-	achievementStore.AddProgression(achievements.StudentAchievement{
+	require.NoError(t, achievementStore.AddProgression(context.Background(), achievements.StudentAchievement{
 		AchievementID: aID,
 		StudentID:     student.ID(),
 		Progress:      achievements.Started,
-	})
-	r := NewRouter(accountStore, achievementStore)
+	}, student.ID()))
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroom.NewInMemoryStore(), newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
 	require.NotNil(t, r)
 
 	t.Run("should return not found for student not present in accounts store", func(t *testing.T) {
 		req, err := http.NewRequest("GET", "/students/student-not-in-store/achievements", nil)
 		require.NoError(t, err)
+		authorise(t, req, sessions, teacher)
 		rr := httptest.NewRecorder()
 		r.ServeHTTP(rr, req)
 		require.Equal(t, http.StatusNotFound, rr.Code)
 	})
 
+	t.Run("should return forbidden when a student asks for another student's achievements", func(t *testing.T) {
+		other := givenAccount(t, accountStore, account.NewStudent("Other Student"))
+		req, err := http.NewRequest("GET", "/students/"+other.ID()+"/achievements", nil)
+		require.NoError(t, err)
+		authorise(t, req, sessions, student)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should let a teacher see a student's achievements", func(t *testing.T) {
+		resp := getStudentAchievementsFromAPIAs(t, r, sessions, teacher, student)
+		assert.Len(t, resp.Achievements, 1)
+	})
+
 	t.Run("should return a student's achievement", func(t *testing.T) {
 		req, err := http.NewRequest("GET", "/students/"+student.ID()+"/achievements", nil)
 		require.NoError(t, err)
+		authorise(t, req, sessions, student)
 		rr := httptest.NewRecorder()
 		r.ServeHTTP(rr, req)
 		require.Equal(t, http.StatusOK, rr.Code)
@@ -113,25 +187,27 @@ func TestGetAchievementsForStudent(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, resp.Achievements, 1)
 		achievement := resp.Achievements[0]
-		assert.Equal(t, "achievement", achievement.Achievement)
+		assert.Equal(t, "achievement", achievement.Achievement.Name)
 		assert.Equal(t, achievements.Started, achievement.Progress)
 
 		rr.Flush()
-		a2ID := achievementStore.CreateAchievement("achievement2")
-		achievementStore.AddProgression(achievements.StudentAchievement{
+		a2ID, err := achievementStore.CreateAchievement(context.Background(), achievements.Achievement{Name: "achievement2"})
+		require.NoError(t, err)
+		require.NoError(t, achievementStore.AddProgression(context.Background(), achievements.StudentAchievement{
 			AchievementID: a2ID,
 			StudentID:     student.ID(),
 			Progress:      achievements.Finished,
-		})
+		}, student.ID()))
 
 		r.ServeHTTP(rr, req)
 		require.Equal(t, http.StatusOK, rr.Code)
 		err = json.NewDecoder(rr.Body).Decode(&resp)
 		require.NoError(t, err)
 		require.Len(t, resp.Achievements, 2)
-		achievement = resp.Achievements[1]
-		assert.Equal(t, "achievement2", achievement.Achievement)
-		assert.Equal(t, achievements.Finished, achievement.Progress)
+		assert.Contains(t, resp.Achievements, progressResponse{
+			Achievement: simpleAchievement{Name: "achievement2", ID: a2ID},
+			Progress:    achievements.Finished,
+		})
 	})
 }
 
@@ -139,28 +215,46 @@ func TestUpdateAchievementProgress(t *testing.T) {
 	accountStore := account.NewInMemoryStore()
 	achievementStore := store.NewInMemory()
 	student := account.NewStudent("Test Login")
-	err := accountStore.SaveAccount(student)
+	err := accountStore.SaveAccount(context.Background(), student)
 	require.NoError(t, err)
-	achievementID := givenAchievement(achievementStore)
-	achievementStore.AddProgression(achievements.StudentAchievement{
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	achievementID := givenAchievement(t, achievementStore)
+	require.NoError(t, achievementStore.AddProgression(context.Background(), achievements.StudentAchievement{
 		AchievementID: achievementID,
 		StudentID:     student.ID(),
 		Progress:      achievements.Started,
-	})
-	r := NewRouter(accountStore, achievementStore)
+	}, student.ID()))
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroom.NewInMemoryStore(), newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
 	require.NotNil(t, r)
 
 	t.Run("should return not found for student not present in accounts store", func(t *testing.T) {
 		req, err := http.NewRequest("PUT", "/students/student-not-in-store/achievements/doesnt-matter/progress", nil)
 		require.NoError(t, err)
+		authorise(t, req, sessions, teacher)
 		rr := httptest.NewRecorder()
 		r.ServeHTTP(rr, req)
 		require.Equal(t, http.StatusNotFound, rr.Code)
 	})
 
+	t.Run("should return forbidden when a student updates another student's progress", func(t *testing.T) {
+		other := givenAccount(t, accountStore, account.NewStudent("Other Student"))
+		body := bytes.NewBufferString(`{"progress": "FINISHED"}`)
+		req, err := http.NewRequest("PUT", fmt.Sprintf("/students/%s/achievements/%s/progress", other.ID(), achievementID), body)
+		require.NoError(t, err)
+		authorise(t, req, sessions, student)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusForbidden, rr.Code)
+		studentAchievements, err := achievementStore.GetStudentAchievements(context.Background(), other.ID())
+		require.NoError(t, err)
+		assert.Empty(t, studentAchievements)
+	})
+
 	t.Run("should return not found for achievement not present in accounts store", func(t *testing.T) {
 		req, err := http.NewRequest("PUT", fmt.Sprintf("/students/%s/achievements/achievement-not-exist/progress", student.ID()), nil)
 		require.NoError(t, err)
+		authorise(t, req, sessions, student)
 		rr := httptest.NewRecorder()
 		r.ServeHTTP(rr, req)
 		require.Equal(t, http.StatusNotFound, rr.Code)
@@ -169,6 +263,7 @@ func TestUpdateAchievementProgress(t *testing.T) {
 	t.Run("should return bad request if missing progress in body", func(t *testing.T) {
 		req, err := http.NewRequest("PUT", fmt.Sprintf("/students/%s/achievements/%s/progress", student.ID(), achievementID), nil)
 		require.NoError(t, err)
+		authorise(t, req, sessions, student)
 		rr := httptest.NewRecorder()
 		r.ServeHTTP(rr, req)
 		require.Equal(t, http.StatusBadRequest, rr.Code)
@@ -178,6 +273,7 @@ func TestUpdateAchievementProgress(t *testing.T) {
 		body := bytes.NewBufferString(`{"progress": "STARTED`)
 		req, err := http.NewRequest("PUT", fmt.Sprintf("/students/%s/achievements/%s/progress", student.ID(), achievementID), body)
 		require.NoError(t, err)
+		authorise(t, req, sessions, student)
 		rr := httptest.NewRecorder()
 		r.ServeHTTP(rr, req)
 		require.Equal(t, http.StatusBadRequest, rr.Code)
@@ -187,41 +283,54 @@ func TestUpdateAchievementProgress(t *testing.T) {
 		body := bytes.NewBufferString(`{"progress": "STARTEDO"}`)
 		req, err := http.NewRequest("PUT", fmt.Sprintf("/students/%s/achievements/%s/progress", student.ID(), achievementID), body)
 		require.NoError(t, err)
+		authorise(t, req, sessions, student)
 		rr := httptest.NewRecorder()
 		r.ServeHTTP(rr, req)
 		require.Equal(t, http.StatusBadRequest, rr.Code)
 	})
 
+	t.Run("should return forbidden when a student finishes their own achievement", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, student, http.MethodPut, fmt.Sprintf("/students/%s/achievements/%s/progress", student.ID(), achievementID), `{"progress": "FINISHED"}`)
+		require.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
 	t.Run("should update progress for valid request", func(t *testing.T) {
-		body := bytes.NewBufferString(`{"progress": "FINISHED"}`)
+		body := bytes.NewBufferString(`{"progress": "SUBMITTED"}`)
 		req, err := http.NewRequest("PUT", fmt.Sprintf("/students/%s/achievements/%s/progress", student.ID(), achievementID), body)
 		require.NoError(t, err)
+		authorise(t, req, sessions, student)
 		rr := httptest.NewRecorder()
 		r.ServeHTTP(rr, req)
 		require.Equal(t, http.StatusOK, rr.Code)
 
-		achvs := getStudentAchievementsFromAPI(t, r, student)
+		achvs := getStudentAchievementsFromAPI(t, r, sessions, student)
 		require.Len(t, achvs.Achievements, 1)
-		assert.Equal(t, achievements.Finished, achvs.Achievements[0].Progress)
+		assert.Equal(t, achievements.Submitted, achvs.Achievements[0].Progress)
 	})
 }
 
-func givenAchievement(achievementStore achievements.Store) string {
+func givenAchievement(t *testing.T, achievementStore achievements.Store) string {
 	aName := "Achievement_" + gonanoid.Must(4)
-	return achievementStore.CreateAchievement(aName)
+	id, err := achievementStore.CreateAchievement(context.Background(), achievements.Achievement{Name: aName})
+	require.NoError(t, err)
+	return id
 }
 
 func TestGetAllAchievements(t *testing.T) {
 	accountStore := account.NewInMemoryStore()
 	achievementStore := store.NewInMemory()
-	achievement1 := givenAchievement(achievementStore)
-	achievement2 := givenAchievement(achievementStore)
-	achievement3 := givenAchievement(achievementStore)
-	r := NewRouter(accountStore, achievementStore)
+	student := account.NewStudent("Test Login")
+	err := accountStore.SaveAccount(context.Background(), student)
+	require.NoError(t, err)
+	achievement1 := givenAchievement(t, achievementStore)
+	achievement2 := givenAchievement(t, achievementStore)
+	achievement3 := givenAchievement(t, achievementStore)
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroom.NewInMemoryStore(), newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
 	require.NotNil(t, r)
 
 	t.Run("should return all achievements present in store", func(t *testing.T) {
-		resp := getAllAchievementsFromAPI(t, r)
+		resp := getAllAchievementsFromAPI(t, r, sessions, student)
 
 		assert.Len(t, resp.Achievements, 3)
 		ids := make([]string, len(resp.Achievements))
@@ -234,9 +343,10 @@ func TestGetAllAchievements(t *testing.T) {
 	})
 }
 
-func getAllAchievementsFromAPI(t *testing.T, r http.Handler) allAchievementsResponse {
+func getAllAchievementsFromAPI(t *testing.T, r http.Handler, sessions *session.Manager, student account.Account) allAchievementsResponse {
 	req, err := http.NewRequest(http.MethodGet, "/achievements", nil)
 	require.NoError(t, err)
+	authorise(t, req, sessions, student)
 	rr := httptest.NewRecorder()
 	r.ServeHTTP(rr, req)
 	require.Equal(t, http.StatusOK, rr.Code)
@@ -250,15 +360,31 @@ func TestCreateAchievement(t *testing.T) {
 	accountStore := account.NewInMemoryStore()
 	achievementStore := store.NewInMemory()
 	student := account.NewStudent("Test Login")
-	err := accountStore.SaveAccount(student)
+	err := accountStore.SaveAccount(context.Background(), student)
 	require.NoError(t, err)
-	r := NewRouter(accountStore, achievementStore)
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroom.NewInMemoryStore(), newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
 	require.NotNil(t, r)
 
+	t.Run("should return forbidden for students", func(t *testing.T) {
+		body := []byte(`{"name": "Write some code"}`)
+		req, err := http.NewRequest(http.MethodPost, "/achievements", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		authorise(t, req, sessions, student)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusForbidden, rr.Code)
+		all, err := achievementStore.GetAllAchievements(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, all)
+	})
+
 	t.Run("should return achievement id on success", func(t *testing.T) {
 		body := []byte(`{"name": "Write some code"}`)
 		req, err := http.NewRequest(http.MethodPost, "/achievements", bytes.NewBuffer(body))
 		require.NoError(t, err)
+		authorise(t, req, sessions, teacher)
 		rr := httptest.NewRecorder()
 		r.ServeHTTP(rr, req)
 		require.Equal(t, http.StatusOK, rr.Code)
@@ -267,19 +393,24 @@ func TestCreateAchievement(t *testing.T) {
 		require.NoError(t, err)
 		require.NotEmpty(t, resp.ID)
 
-		assert.True(t, achievementStore.AchievementExists(resp.ID))
-		allResp := getAllAchievementsFromAPI(t, r)
-		assert.Len(t, allResp.Achievements, 1)
-		assert.Contains(t, allResp.Achievements, achievements.Achievement{
-			ID:   resp.ID,
-			Name: "Write some code",
-		})
+		exists, err := achievementStore.AchievementExists(context.Background(), resp.ID)
+		require.NoError(t, err)
+		assert.True(t, exists)
+		allResp := getAllAchievementsFromAPI(t, r, sessions, student)
+		require.Len(t, allResp.Achievements, 1)
+		assert.Equal(t, resp.ID, allResp.Achievements[0].ID)
+		assert.Equal(t, "Write some code", allResp.Achievements[0].Name)
 	})
 }
 
-func getStudentAchievementsFromAPI(t *testing.T, r http.Handler, student account.Account) achievementResponse {
+func getStudentAchievementsFromAPI(t *testing.T, r http.Handler, sessions *session.Manager, student account.Account) achievementResponse {
+	return getStudentAchievementsFromAPIAs(t, r, sessions, student, student)
+}
+
+func getStudentAchievementsFromAPIAs(t *testing.T, r http.Handler, sessions *session.Manager, as, student account.Account) achievementResponse {
 	req, err := http.NewRequest(http.MethodGet, "/students/"+student.ID()+"/achievements", nil)
 	require.NoError(t, err)
+	authorise(t, req, sessions, as)
 	rr := httptest.NewRecorder()
 	r.ServeHTTP(rr, req)
 	require.Equal(t, http.StatusOK, rr.Code)
@@ -288,3 +419,231 @@ func getStudentAchievementsFromAPI(t *testing.T, r http.Handler, student account
 	require.NoError(t, err)
 	return resp
 }
+
+func newTestSessions() *session.Manager {
+	return session.NewManager([]byte("test secret"), time.Hour)
+}
+
+func newTestCodes(t *testing.T) *account.CodeGenerator {
+	codes, err := account.NewCodeGenerator(account.DefaultCodeOptions)
+	require.NoError(t, err)
+	return codes
+}
+
+func newTestGuard() *throttle.Guard {
+	return throttle.NewGuard(throttle.DefaultPolicy)
+}
+
+func newTestLocker() *evidence.Locker {
+	return evidence.NewLocker(evidence.NewInMemoryStore(), evidence.NewInMemoryBlobs(), evidence.DefaultLimits)
+}
+
+// authorise adds a session token for acc to req, as the frontend
+// would after logging in.
+func authorise(t *testing.T, req *http.Request, sessions *session.Manager, acc account.Account) {
+	token, err := sessions.Issue(acc.ID(), acc.Code())
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
+func givenAccount(t *testing.T, accountStore account.Store, acc account.Account) account.Account {
+	err := accountStore.SaveAccount(context.Background(), acc)
+	require.NoError(t, err)
+	return acc
+}
+
+func TestAchievementDueDates(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	achievementStore := store.NewInMemory()
+	student := givenAccount(t, accountStore, account.NewStudent("Test Login"))
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	other := givenAccount(t, accountStore, account.NewStudent("Other Student"))
+	classroomStore := classroom.NewInMemoryStore()
+	classID := givenClassroom(t, classroomStore, "Class 3B", teacher.ID())
+	require.NoError(t, classroomStore.AddStudent(context.Background(), classID, student.ID()))
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroomStore, newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
+	lastWeek := time.Now().Add(-7 * 24 * time.Hour).UTC().Truncate(time.Second)
+	nextWeek := time.Now().Add(7 * 24 * time.Hour).UTC().Truncate(time.Second)
+
+	var achievementID string
+	t.Run("should create an achievement with a due date", func(t *testing.T) {
+		body := fmt.Sprintf(`{"name": "Plant some Seeds", "dueDate": "%s"}`, nextWeek.Format(time.RFC3339))
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, "/achievements", body)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp createAchievementResponse
+		err := json.NewDecoder(rr.Body).Decode(&resp)
+		require.NoError(t, err)
+		achievementID = resp.ID
+
+		a, err := achievementStore.GetAchievement(context.Background(), achievementID)
+		require.NoError(t, err)
+		require.NotNil(t, a.DueDate)
+		assert.True(t, nextWeek.Equal(*a.DueDate))
+	})
+
+	t.Run("should return bad request for an invalid due date", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, "/achievements", `{"name": "Plant some Seeds", "dueDate": "next week"}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should include due date and overdue status in student achievements", func(t *testing.T) {
+		require.NoError(t, achievementStore.AddProgression(context.Background(), achievements.StudentAchievement{
+			AchievementID: achievementID,
+			StudentID:     student.ID(),
+			Progress:      achievements.Started,
+		}, student.ID()))
+		resp := getStudentAchievementsFromAPI(t, r, sessions, student)
+		require.Len(t, resp.Achievements, 1)
+		require.NotNil(t, resp.Achievements[0].DueDate)
+		assert.True(t, nextWeek.Equal(*resp.Achievements[0].DueDate))
+		assert.False(t, resp.Achievements[0].Overdue)
+	})
+
+	t.Run("should only let teachers change due dates", func(t *testing.T) {
+		body := fmt.Sprintf(`{"dueDate": "%s"}`, lastWeek.Format(time.RFC3339))
+		rr := doRequest(t, r, sessions, student, http.MethodPut, "/achievements/"+achievementID+"/due-date", body)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should return not found when changing the due date of an unknown achievement", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPut, "/achievements/not-an-achievement/due-date", `{"dueDate": null}`)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should mark achievements past their due date as overdue", func(t *testing.T) {
+		body := fmt.Sprintf(`{"dueDate": "%s"}`, lastWeek.Format(time.RFC3339))
+		rr := doRequest(t, r, sessions, teacher, http.MethodPut, "/achievements/"+achievementID+"/due-date", body)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		resp := getStudentAchievementsFromAPI(t, r, sessions, student)
+		require.Len(t, resp.Achievements, 1)
+		assert.True(t, resp.Achievements[0].Overdue)
+	})
+
+	t.Run("should list overdue unfinished achievements of students in the teacher's classes", func(t *testing.T) {
+		require.NoError(t, achievementStore.AddProgression(context.Background(), achievements.StudentAchievement{
+			AchievementID: achievementID,
+			StudentID:     other.ID(),
+			Progress:      achievements.Started,
+		}, other.ID()))
+		rr := doRequest(t, r, sessions, student, http.MethodGet, "/achievements/overdue", "")
+		require.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = doRequest(t, r, sessions, teacher, http.MethodGet, "/achievements/overdue", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp overdueResponse
+		err := json.NewDecoder(rr.Body).Decode(&resp)
+		require.NoError(t, err)
+		require.Len(t, resp.Overdue, 1)
+		assert.Equal(t, simpleAccount{ID: student.ID(), Name: student.Name()}, resp.Overdue[0].Student)
+		assert.Equal(t, achievementID, resp.Overdue[0].Achievement.ID)
+		assert.Equal(t, achievements.Started, resp.Overdue[0].Progress)
+		assert.True(t, lastWeek.Equal(resp.Overdue[0].DueDate))
+	})
+
+	t.Run("should not list finished achievements as overdue", func(t *testing.T) {
+		require.NoError(t, achievementStore.AddProgression(context.Background(), achievements.StudentAchievement{
+			AchievementID: achievementID,
+			StudentID:     student.ID(),
+			Progress:      achievements.Finished,
+		}, student.ID()))
+		rr := doRequest(t, r, sessions, teacher, http.MethodGet, "/achievements/overdue", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var resp overdueResponse
+		err := json.NewDecoder(rr.Body).Decode(&resp)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Overdue)
+	})
+
+	t.Run("should clear a due date", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPut, "/achievements/"+achievementID+"/due-date", `{"dueDate": null}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		a, err := achievementStore.GetAchievement(context.Background(), achievementID)
+		require.NoError(t, err)
+		assert.Nil(t, a.DueDate)
+	})
+}
+
+func TestUpdateAchievement(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	achievementStore := store.NewInMemory()
+	student := givenAccount(t, accountStore, account.NewStudent("Test Login"))
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroom.NewInMemoryStore(), newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
+	achievementID, err := achievementStore.CreateAchievement(context.Background(), achievements.Achievement{Name: "Stitch up a Hole in some Clothign"})
+	require.NoError(t, err)
+
+	t.Run("should return forbidden for students", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, student, http.MethodPut, "/achievements/"+achievementID, `{"name": "Mine now"}`)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should return bad request without a name", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPut, "/achievements/"+achievementID, `{"name": ""}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should return not found for unknown achievements", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPut, "/achievements/not-an-achievement", `{"name": "name"}`)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should update the achievement", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPut, "/achievements/"+achievementID, `{"name": "Stitch up a Hole in some Clothing", "points": 3}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		a, err := achievementStore.GetAchievement(context.Background(), achievementID)
+		require.NoError(t, err)
+		assert.Equal(t, "Stitch up a Hole in some Clothing", a.Name)
+		assert.Equal(t, 3, a.Points)
+	})
+}
+
+func TestArchiveAchievement(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	achievementStore := store.NewInMemory()
+	student := givenAccount(t, accountStore, account.NewStudent("Test Login"))
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroom.NewInMemoryStore(), newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
+	achievementID := givenAchievement(t, achievementStore)
+	require.NoError(t, achievementStore.AddProgression(context.Background(), achievements.StudentAchievement{
+		AchievementID: achievementID,
+		StudentID:     student.ID(),
+		Progress:      achievements.Finished,
+	}, student.ID()))
+
+	t.Run("should return forbidden for students", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, student, http.MethodDelete, "/achievements/"+achievementID, "")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should archive the achievement", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodDelete, "/achievements/"+achievementID, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, getAllAchievementsFromAPI(t, r, sessions, teacher).Achievements)
+	})
+
+	t.Run("should keep the student's history of an archived achievement", func(t *testing.T) {
+		resp := getStudentAchievementsFromAPI(t, r, sessions, student)
+		require.Len(t, resp.Achievements, 1)
+		assert.Equal(t, achievementID, resp.Achievements[0].Achievement.ID)
+		assert.NotEmpty(t, resp.Achievements[0].Achievement.Name)
+		assert.Equal(t, achievements.Finished, resp.Achievements[0].Progress)
+		assert.True(t, resp.Achievements[0].Archived)
+	})
+
+	t.Run("should not allow progress on an archived achievement", func(t *testing.T) {
+		url := fmt.Sprintf("/students/%s/achievements/%s/progress", student.ID(), achievementID)
+		rr := doRequest(t, r, sessions, student, http.MethodPut, url, `{"progress": "STARTED"}`)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should return not found for unknown or already archived achievements", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodDelete, "/achievements/"+achievementID, "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodDelete, "/achievements/not-an-achievement", "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,107 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/go-chi/chi/v5"
+)
+
+type reviewRequest struct {
+	Comment string `json:"comment"`
+}
+
+// reviewSubmission approves a student's submitted achievement, finishing
+// it, or rejects it back to started. Rejections must say why.
+func reviewSubmission(accountStore account.Store, achievementStore achievements.Store, approve bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		id := chi.URLParam(req, "id")
+		if err := requireStudent(req.Context(), accountStore, id); err != nil {
+			writeError(w, err)
+			return
+		}
+		achievementID := chi.URLParam(req, "achievement")
+		if err := requireAchievement(req.Context(), achievementStore, achievementID); err != nil {
+			writeError(w, err)
+			return
+		}
+		var reviewReq reviewRequest
+		if req.ContentLength != 0 {
+			err := json.NewDecoder(req.Body).Decode(&reviewReq)
+			if err != nil {
+				writeError(w, errInvalidJSON)
+				return
+			}
+		}
+		if !approve && strings.TrimSpace(reviewReq.Comment) == "" {
+			writeError(w, invalidField("comment", "is required to reject a submission"))
+			return
+		}
+		err := achievementStore.ReviewSubmission(req.Context(), achievements.Review{
+			StudentID:     id,
+			AchievementID: achievementID,
+			Approved:      approve,
+			Comment:       reviewReq.Comment,
+			ReviewerID:    accountFromContext(req.Context()).ID(),
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
+
+type pendingSubmission struct {
+	Student     simpleAccount     `json:"student"`
+	Achievement simpleAchievement `json:"achievement"`
+	SubmittedAt time.Time         `json:"submittedAt"`
+}
+
+type pendingSubmissionsResponse struct {
+	Submissions []pendingSubmission `json:"submissions"`
+}
+
+// getPendingSubmissions lists achievements waiting for review by the
+// students in the teacher's classes, oldest submission first.
+func getPendingSubmissions(accountStore account.Store, achievementStore achievements.Store, classroomStore classroom.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		teacher := accountFromContext(req.Context())
+		studentIDs, err := teacherStudentIDs(req.Context(), classroomStore, teacher.ID())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		submissions, err := achievementStore.GetSubmissions(req.Context(), studentIDs)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		names, err := studentNames(req.Context(), accountStore)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		pending := make([]pendingSubmission, 0, len(submissions))
+		for _, sub := range submissions {
+			name, ok := names[sub.StudentID]
+			if !ok {
+				continue
+			}
+			pending = append(pending, pendingSubmission{
+				Student:     simpleAccount{ID: sub.StudentID, Name: name},
+				Achievement: simpleAchievement{Name: sub.Achievement.Name, ID: sub.Achievement.ID},
+				SubmittedAt: sub.SubmittedAt,
+			})
+		}
+		err = json.NewEncoder(w).Encode(pendingSubmissionsResponse{Submissions: pending})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Manchester-Dev/medlock/internal/account"
+	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/session"
+	"github.com/Manchester-Dev/medlock/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmissionReview(t *testing.T) {
+	accountStore := account.NewInMemoryStore()
+	achievementStore := store.NewInMemory()
+	classroomStore := classroom.NewInMemoryStore()
+	teacher := givenAccount(t, accountStore, account.NewTeacher("Test Teacher"))
+	otherTeacher := givenAccount(t, accountStore, account.NewTeacher("Other Teacher"))
+	student := givenAccount(t, accountStore, account.NewStudent("Student A"))
+	classID := givenClassroom(t, classroomStore, "Class 3B", teacher.ID())
+	require.NoError(t, classroomStore.AddStudent(context.Background(), classID, student.ID()))
+	sessions := newTestSessions()
+	r := NewRouter(accountStore, achievementStore, classroomStore, newTestLocker(), newTestCodes(t), newTestGuard(), sessions)
+	achievementID := givenAchievement(t, achievementStore)
+	progressURL := fmt.Sprintf("/students/%s/achievements/%s/progress", student.ID(), achievementID)
+	approveURL := fmt.Sprintf("/students/%s/achievements/%s/approve", student.ID(), achievementID)
+	rejectURL := fmt.Sprintf("/students/%s/achievements/%s/reject", student.ID(), achievementID)
+
+	t.Run("should return conflict when nothing has been submitted", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, approveURL, "")
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	rr := doRequest(t, r, sessions, student, http.MethodPut, progressURL, `{"progress": "SUBMITTED"}`)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	t.Run("should list the submission for the student's teacher only", func(t *testing.T) {
+		pending := getPendingSubmissionsFromAPI(t, r, sessions, teacher)
+		require.Len(t, pending, 1)
+		assert.Equal(t, simpleAccount{ID: student.ID(), Name: "Student A"}, pending[0].Student)
+		assert.Equal(t, achievementID, pending[0].Achievement.ID)
+		assert.False(t, pending[0].SubmittedAt.IsZero())
+		assert.Empty(t, getPendingSubmissionsFromAPI(t, r, sessions, otherTeacher))
+	})
+
+	t.Run("should return forbidden for students", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, student, http.MethodPost, approveURL, "")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		rr = doRequest(t, r, sessions, student, http.MethodGet, "/submissions", "")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should return bad request when rejecting without a comment", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, rejectURL, `{"comment": " "}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should send a rejected submission back to started", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, rejectURL, `{"comment": "Add a photo of the seedlings"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		achvs := getStudentAchievementsFromAPI(t, r, sessions, student)
+		require.Len(t, achvs.Achievements, 1)
+		assert.Equal(t, achievements.Started, achvs.Achievements[0].Progress)
+		assert.Empty(t, getPendingSubmissionsFromAPI(t, r, sessions, teacher))
+
+		rr = doRequest(t, r, sessions, student, http.MethodGet, fmt.Sprintf("/students/%s/achievements/%s/history", student.ID(), achievementID), "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var history historyResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&history))
+		last := history.History[len(history.History)-1]
+		assert.Equal(t, "Add a photo of the seedlings", last.Comment)
+		assert.Equal(t, teacher.ID(), last.Actor.ID)
+	})
+
+	t.Run("should finish an approved submission", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, student, http.MethodPut, progressURL, `{"progress": "SUBMITTED"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodPost, approveURL, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		achvs := getStudentAchievementsFromAPI(t, r, sessions, student)
+		require.Len(t, achvs.Achievements, 1)
+		assert.Equal(t, achievements.Finished, achvs.Achievements[0].Progress)
+	})
+
+	t.Run("should return not found for unknown students or achievements", func(t *testing.T) {
+		rr := doRequest(t, r, sessions, teacher, http.MethodPost, "/students/not-a-student/achievements/"+achievementID+"/approve", "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		rr = doRequest(t, r, sessions, teacher, http.MethodPost, "/students/"+student.ID()+"/achievements/not-an-achievement/approve", "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func getPendingSubmissionsFromAPI(t *testing.T, r http.Handler, sessions *session.Manager, teacher account.Account) []pendingSubmission {
+	rr := doRequest(t, r, sessions, teacher, http.MethodGet, "/submissions", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp pendingSubmissionsResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	require.NoError(t, err)
+	return resp.Submissions
+}
@@ -1,16 +1,60 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/Manchester-Dev/medlock/internal/account"
 	"github.com/Manchester-Dev/medlock/internal/achievements"
+	"github.com/Manchester-Dev/medlock/internal/classroom"
+	"github.com/Manchester-Dev/medlock/internal/database"
+	"github.com/Manchester-Dev/medlock/internal/evidence"
+	"github.com/Manchester-Dev/medlock/internal/roster"
+	"github.com/Manchester-Dev/medlock/internal/session"
 	"github.com/Manchester-Dev/medlock/internal/store"
+	"github.com/Manchester-Dev/medlock/internal/throttle"
 	"github.com/Manchester-Dev/medlock/internal/web"
+	"io"
 	"math/rand"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 )
 
+// sessionTTL is how long a login lasts, long enough to cover a school day.
+const sessionTTL = 12 * time.Hour
+
+// codeSpaceWarning is how full the space of login codes can get before
+// a warning is printed at startup.
+const codeSpaceWarning = 0.1
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import-students" {
+		err := importStudents(os.Args[2:])
+		if verr, ok := err.(*roster.ValidationError); ok {
+			for _, r := range verr.Rows {
+				fmt.Fprintf(os.Stderr, "row %d: %s\n", r.Row, r.Message)
+			}
+			os.Exit(1)
+		}
+		check(err)
+		return
+	}
+	serve()
+}
+
+func serve() {
+	storeKind := flag.String("store", envOr("MEDLOCK_STORE", "memory"), "storage backend for accounts, achievements and classes: memory or sqlite (env MEDLOCK_STORE)")
+	dbPath := flag.String("db", envOr("MEDLOCK_DB", "medlock.db"), "path of the SQLite database file (env MEDLOCK_DB)")
+	secretPath := flag.String("session-secret", envOr("MEDLOCK_SESSION_SECRET", "session.key"), "file holding the secret used to sign session tokens, created if missing (env MEDLOCK_SESSION_SECRET)")
+	evidenceDir := flag.String("evidence-dir", envOr("MEDLOCK_EVIDENCE_DIR", "evidence"), "directory uploaded evidence files are kept in, created if missing (env MEDLOCK_EVIDENCE_DIR)")
+	newCodes := codeFlags(flag.CommandLine)
+	flag.Parse()
+	codes, err := newCodes()
+	check(err)
+
 	aa := []achievements.Progress{
 		achievements.Started,
 		achievements.Finished,
@@ -26,29 +70,210 @@ func main() {
 		"Make a Sculpture out of Bottle Caps",
 		"Donate Old Clothing",
 	}
-	accountStore := account.NewInMemoryStore()
-	student := account.NewStudent("Test Login")
-	teacher := account.NewTeacher("Test Teacher")
-	err := accountStore.SaveAccount(student)
+	accountStore, achvStore, classroomStore, evidenceStore, err := newStores(*storeKind, *dbPath)
+	check(err)
+	ctx := context.Background()
+	empty, err := isEmpty(ctx, accountStore, achvStore)
+	check(err)
+	if !empty {
+		fmt.Printf("using existing accounts and achievements from %s\n", *dbPath)
+	} else {
+		student, err := codes.CreateAccount(ctx, accountStore, "Test Login", account.RoleStudent)
+		check(err)
+		teacher, err := codes.CreateAccount(ctx, accountStore, "Test Teacher", account.RoleTeacher)
+		check(err)
+		for i := 0; i < 9; i++ {
+			id, err := achvStore.CreateAchievement(ctx, achievements.Achievement{Name: achvs[i]})
+			check(err)
+			r := rand.Int() % 2
+			err = achvStore.AddProgression(ctx, achievements.StudentAchievement{
+				AchievementID: id,
+				StudentID:     student.ID(),
+				Progress:      aa[r],
+			}, teacher.ID())
+			check(err)
+		}
+		fmt.Printf("stored student with code: %s\n", student.Code())
+		fmt.Printf("stored teacher with code: %s\n", teacher.Code())
+	}
+	usage, err := codes.Usage(ctx, accountStore)
+	check(err)
+	if usage.Fullness > codeSpaceWarning {
+		fmt.Printf("warning: %.0f%% of login codes are in use, consider a longer -code-length\n", usage.Fullness*100)
+	}
+	secret, err := session.LoadOrCreateSecret(*secretPath)
 	check(err)
-	err = accountStore.SaveAccount(teacher)
+	sessions := session.NewManager(secret, sessionTTL)
+	blobs, err := evidence.NewDiskBlobs(*evidenceDir)
 	check(err)
-	achvStore := store.NewInMemory()
-	for i := 0; i < 9; i++ {
-		id := achvStore.CreateAchievement(achvs[i])
-		r := rand.Int() % 2
-		achvStore.AddProgression(achievements.StudentAchievement{
-			AchievementID: id,
-			StudentID:     student.ID(),
-			Progress:      aa[r],
-		})
-	}
-	fmt.Printf("stored student with code: %s\n", student.Code())
-	fmt.Printf("stored teacher with code: %s\n", teacher.Code())
-	r := web.NewRouter(accountStore, achvStore)
+	locker := evidence.NewLocker(evidenceStore, blobs, evidence.DefaultLimits)
+	r := web.NewRouter(accountStore, achvStore, classroomStore, locker, codes, throttle.NewGuard(throttle.DefaultPolicy), sessions)
 	http.ListenAndServe(":4000", r)
 }
 
+// isEmpty reports whether there are no accounts and no achievements,
+// archived or not, so demo data is only ever added to a new database.
+func isEmpty(ctx context.Context, accountStore account.Store, achvStore achievements.Store) (bool, error) {
+	accounts, err := accountStore.CountAccounts(ctx)
+	if err != nil || accounts > 0 {
+		return false, err
+	}
+	aa, err := achvStore.ListAchievements(ctx, achievements.ListOptions{Limit: 1})
+	if err != nil {
+		return false, err
+	}
+	return len(aa) == 0, nil
+}
+
+// importStudents creates student accounts from a CSV file, printing a
+// CSV of their login codes. Students with a class are added to the
+// teacher's class of that name, as they are by the API. Accounts are
+// only kept by the sqlite store, so it is the only one supported.
+func importStudents(args []string) error {
+	flags := flag.NewFlagSet("import-students", flag.ExitOnError)
+	dbPath := flags.String("db", envOr("MEDLOCK_DB", "medlock.db"), "path of the SQLite database file (env MEDLOCK_DB)")
+	out := flags.String("o", "-", "file to write the login codes to, - for standard output")
+	teacherCode := flags.String("teacher", "", "login code of the teacher whose classes students are added to, needed when the CSV has classes")
+	newCodes := codeFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: %s import-students [-db medlock.db] [-o codes.csv] [-teacher code] students.csv\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	codes, err := newCodes()
+	if err != nil {
+		return err
+	}
+
+	var in io.Reader = os.Stdin
+	if name := flags.Arg(0); name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	students, err := roster.Parse(in)
+	if err != nil {
+		return err
+	}
+	accountStore, achvStore, classroomStore, _, err := newStores("sqlite", *dbPath)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	var teacher account.Account
+	if *teacherCode != "" {
+		teacher, err = accountStore.Login(ctx, *teacherCode)
+		if errors.Is(err, account.ErrNotFound) || (err == nil && teacher.Role() != account.RoleTeacher) {
+			return fmt.Errorf("no teacher has the login code %q", *teacherCode)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, s := range students {
+		if s.Class != "" && teacher == nil {
+			return errors.New("the students have classes, give the login code of the teacher running them with -teacher")
+		}
+	}
+	var teacherID string
+	if teacher != nil {
+		teacherID = teacher.ID()
+	}
+	accounts, err := roster.Enrol(ctx, codes, accountStore, classroomStore, achvStore, teacherID, students)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return roster.WriteCodes(w, students, accounts)
+}
+
+// codeFlags adds the flags configuring login codes to flags. The
+// returned function makes the configured generator once they have been
+// parsed.
+func codeFlags(flags *flag.FlagSet) func() (*account.CodeGenerator, error) {
+	opts := account.DefaultCodeOptions
+	length := flags.Int("code-length", opts.Length, "number of characters in new login codes (env MEDLOCK_CODE_LENGTH)")
+	alphabet := flags.String("code-alphabet", envOr("MEDLOCK_CODE_ALPHABET", opts.Alphabet), "characters new login codes are made from (env MEDLOCK_CODE_ALPHABET)")
+	excludeAmbiguous := flags.Bool("code-exclude-ambiguous", os.Getenv("MEDLOCK_CODE_EXCLUDE_AMBIGUOUS") == "true", "leave 0, O, 1 and I out of new login codes (env MEDLOCK_CODE_EXCLUDE_AMBIGUOUS)")
+	return func() (*account.CodeGenerator, error) {
+		opts.Length = *length
+		if v, ok := os.LookupEnv("MEDLOCK_CODE_LENGTH"); ok && !isFlagSet(flags, "code-length") {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("MEDLOCK_CODE_LENGTH: %w", err)
+			}
+			opts.Length = n
+		}
+		opts.Alphabet = *alphabet
+		opts.ExcludeAmbiguous = *excludeAmbiguous
+		return account.NewCodeGenerator(opts)
+	}
+}
+
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func newStores(kind, dbPath string) (account.Store, achievements.Store, classroom.Store, evidence.Store, error) {
+	switch kind {
+	case "memory":
+		return account.NewInMemoryStore(), store.NewInMemory(), classroom.NewInMemoryStore(), evidence.NewInMemoryStore(), nil
+	case "sqlite":
+		db, err := database.Open(dbPath)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		accountStore, err := account.NewSQLiteStore(db)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		achvStore, err := store.NewSQLite(db)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		classroomStore, err := classroom.NewSQLiteStore(db)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		evidenceStore, err := evidence.NewSQLiteStore(db)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		return accountStore, achvStore, classroomStore, evidenceStore, nil
+	default:
+		return nil, nil, nil, nil, fmt.Errorf("unknown store %q, expected memory or sqlite", kind)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
 func check(err error) {
 	if err != nil {
 		panic(err)
@@ -1,7 +1,15 @@
-export type Progress = "" | "STARTED" | "FINISHED"
+export type Progress = "" | "STARTED" | "SUBMITTED" | "FINISHED"
 
 export interface Achievement {
   id: string
   name: string
   progress: Progress
+}
+
+export interface Submission {
+  studentId: string
+  studentName: string
+  achievementId: string
+  achievementName: string
+  submittedAt: string
 }
\ No newline at end of file
@@ -0,0 +1,18 @@
+import {authHeaders, saveToken, tokenCookie} from "./client";
+
+describe("authHeaders", () => {
+
+  afterEach(() => {
+    document.cookie = `${tokenCookie}=;Path=/;Expires=Thu, 01 Jan 1970 00:00:01 GMT;`;
+  });
+
+  it("should send no authorization before login", () => {
+    expect(authHeaders()).toEqual({});
+  });
+
+  it("should send the saved token as a bearer token", () => {
+    saveToken("abc.def");
+    expect(authHeaders()).toEqual({Authorization: "Bearer abc.def"});
+  });
+
+});
@@ -0,0 +1,24 @@
+import axios from "axios";
+import cookieCutter from "cookie-cutter";
+
+export const tokenCookie = "token"
+
+// saveToken keeps the session token login returned, so it is sent with
+// every later request and survives a reload like the account cookie.
+export const saveToken = (token: string) => {
+  cookieCutter.set(tokenCookie, token)
+}
+
+export const authHeaders = (): Record<string, string> => {
+  const token = cookieCutter.get(tokenCookie)
+  return token ? {Authorization: `Bearer ${token}`} : {}
+}
+
+const client = axios.create({baseURL: "http://localhost:4000"})
+
+client.interceptors.request.use(config => {
+  config.headers = {...config.headers, ...authHeaders()}
+  return config
+})
+
+export default client
@@ -1,5 +1,6 @@
 import {useRouter} from "next/router";
 import React, {createContext, useContext, useEffect, useState} from "react";
+import {tokenCookie} from "../api/client";
 
 export type UserType = "Teacher" | "Student"
 
@@ -52,6 +53,7 @@ const AuthContext = ({children, initialUser}: AuthContextProps) => {
   const signOut = () => {
     setUser(undefined);
     clearCookie("account");
+    clearCookie(tokenCookie);
   };
   const isAuth = (): boolean => !!user;
   const isTeacher = (): boolean => !!user && user.isTeacher();
@@ -13,21 +13,34 @@ interface ProgressParams {
   achievement: String
 }
 
+// Students cannot finish an achievement themselves, so the last step
+// submits it for their teacher to approve.
 const ProgressCmp = ({progress, id, achievement}: ProgressParams) => {
   const [p, setP] = useState<Progress>(progress)
+  const [showError, setShowError] = useState(false)
+  const done = p === "SUBMITTED" || p === "FINISHED"
 
-  const handleUpdate = async (p: Progress) => {
-    const callSucceed = await api.updateProgress(p, id, achievement)
+  const handleUpdate = async (next: Progress) => {
+    if (next === "SUBMITTED" && done) {
+      return
+    }
+    const callSucceed = await api.updateProgress(next, id, achievement)
+    setShowError(!callSucceed)
     if (!callSucceed) {
       return
     }
-    setP(p)
+    setP(next)
   }
 
-  return <div className={"flex justify-between px-2 py-2 m-2 bg-gray-500 rounded-lg max-w-min"}>
-    <div className={`w-5 m-1 p-5 rounded-full ${p === "" ? "bg-red-500" : "bg-red-400/50"} hover:bg-red-500`} onClick={() => handleUpdate("")}/>
-    <div className={`w-5 m-1 p-5 rounded-full ${p === "STARTED" ? "bg-yellow-400" : "bg-yellow-400/50"} hover:bg-yellow-400`} onClick={() => handleUpdate("STARTED")}/>
-    <div className={`w-5 m-1 p-5 rounded-full ${p === "FINISHED" ? "bg-green-400" : "bg-green-600/50"} hover:bg-green-400`} onClick={() => handleUpdate("FINISHED")}/>
+  return <div className={"flex flex-col items-center"}>
+    <div className={"flex justify-between px-2 py-2 m-2 bg-gray-500 rounded-lg max-w-min"}>
+      <div className={`w-5 m-1 p-5 rounded-full ${p === "" ? "bg-red-500" : "bg-red-400/50"} hover:bg-red-500`} onClick={() => handleUpdate("")}/>
+      <div className={`w-5 m-1 p-5 rounded-full ${p === "STARTED" ? "bg-yellow-400" : "bg-yellow-400/50"} hover:bg-yellow-400`} onClick={() => handleUpdate("STARTED")}/>
+      <div className={`w-5 m-1 p-5 rounded-full ${done ? "bg-green-400" : "bg-green-600/50"} hover:bg-green-400`} onClick={() => handleUpdate("SUBMITTED")}/>
+    </div>
+    {p === "SUBMITTED" && <p className={"text-sm"}>Waiting for your teacher to check</p>}
+    {p === "FINISHED" && <p className={"text-sm"}>Finished!</p>}
+    {showError && <p className={"text-sm bg-red-200 p-1"}>Could not update your progress</p>}
   </div>
 }
 
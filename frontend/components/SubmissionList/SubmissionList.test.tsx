@@ -0,0 +1,65 @@
+import {fireEvent, render, waitFor} from "@testing-library/react";
+import SubmissionList from "./SubmissionList";
+import {RouterContext} from "next/dist/shared/lib/router-context";
+import * as React from "react";
+import {MemoryRouter} from "next-router-mock";
+import {mockUser} from "../../test/mock-utils";
+import auth from "../../app/user/auth";
+import hooks from "../../hooks/useSubmissions/useSubmissions";
+import review from "../../hooks/reviewSubmission/reviewSubmission";
+import {Submission} from "../../app/achievement/achievement";
+
+const {AuthContext} = auth;
+
+describe("Submission List", () => {
+
+  const renderForTeacher = () => {
+    const router = new MemoryRouter("/dashboard");
+    return render(
+      <RouterContext.Provider value={router}>
+        <AuthContext initialUser={mockUser({type: "Teacher"})}>
+          <SubmissionList/>
+        </AuthContext>
+      </RouterContext.Provider>
+    )
+  }
+
+  beforeEach(() => {
+    jest.spyOn(hooks, "useSubmissions").mockImplementation((): Promise<Submission[] | undefined> =>
+      Promise.resolve([{
+        studentId: "stu001",
+        studentName: "Alice",
+        achievementId: "ach001",
+        achievementName: "Plant some Seeds",
+        submittedAt: "2022-02-01T00:00:00Z",
+      }])
+    );
+  })
+
+  afterEach(jest.restoreAllMocks);
+
+  it("should list submissions waiting for review", async () => {
+    const screen = renderForTeacher()
+    expect(await screen.findByTestId("Plant some Seeds_submission")).toBeInTheDocument()
+    expect(await screen.findByText("Alice: Plant some Seeds")).toBeInTheDocument()
+  })
+
+  it("should approve a submission and remove it from the list", async () => {
+    jest.spyOn(review, "reviewSubmission").mockImplementation((): Promise<Boolean> => Promise.resolve(true));
+    const screen = renderForTeacher()
+    fireEvent.click(await screen.findByText("Approve"))
+    await waitFor(() => expect(review.reviewSubmission).toHaveBeenCalledWith(true, "stu001", "ach001", ""))
+    expect(await screen.findByText("Nothing to review")).toBeInTheDocument()
+  })
+
+  it("should only reject a submission with a reason", async () => {
+    jest.spyOn(review, "reviewSubmission").mockImplementation((): Promise<Boolean> => Promise.resolve(true));
+    const screen = renderForTeacher()
+    const reject = await screen.findByText("Reject") as HTMLButtonElement
+    expect(reject.disabled).toEqual(true)
+    fireEvent.change(screen.getByPlaceholderText("Reason..."), {target: {value: "Needs a photo"}})
+    expect(reject.disabled).toEqual(false)
+    fireEvent.click(reject)
+    await waitFor(() => expect(review.reviewSubmission).toHaveBeenCalledWith(false, "stu001", "ach001", "Needs a photo"))
+  })
+})
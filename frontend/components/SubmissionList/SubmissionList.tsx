@@ -0,0 +1,76 @@
+import api from "../../hooks/useSubmissions/useSubmissions";
+import review from "../../hooks/reviewSubmission/reviewSubmission";
+import {useEffect, useState} from "react";
+import {Submission} from "../../app/achievement/achievement";
+import auth from "../../app/user/auth";
+
+const {useRequireAuth, OnlyTeacher} = auth;
+
+interface SubmissionRowParams {
+  submission: Submission
+  onReviewed: () => void
+}
+
+const SubmissionRow = ({submission, onReviewed}: SubmissionRowParams) => {
+  const [comment, setComment] = useState("")
+  const [showError, setShowError] = useState(false)
+
+  const handleReview = async (approve: boolean) => {
+    const callSucceed = await review.reviewSubmission(approve, submission.studentId, submission.achievementId, comment)
+    setShowError(!callSucceed)
+    if (!callSucceed) {
+      return
+    }
+    onReviewed()
+  }
+
+  return <div className="p-3 flex text-left text-xl border my-1 w-full bg-gray-100 justify-between items-center gap-2">
+    <span>{submission.studentName}: {submission.achievementName}</span>
+    <input className="text-base p-1 rounded" type="text" placeholder="Reason..." value={comment}
+           onChange={(e) => setComment(e.target.value)}/>
+    <div className="flex gap-2">
+      <button className="bg-green-300 hover:bg-green-400 rounded-lg px-3" onClick={() => handleReview(true)}>Approve</button>
+      <button className={`${comment.trim() ? "bg-red-300 hover:bg-red-400" : "bg-gray-300 text-gray-500"} rounded-lg px-3`}
+              disabled={!comment.trim()} onClick={() => handleReview(false)}>Reject
+      </button>
+    </div>
+    {showError && <p className="text-sm bg-red-200 p-1">Could not review this submission</p>}
+  </div>
+}
+
+// render lists the submissions waiting for the teacher to approve, or
+// reject with a reason, so students' achievements can be finished.
+const render = () => {
+  const user = useRequireAuth();
+  const [submissions, setSubmissions] = useState<Submission[]>([])
+  useEffect(() => {
+    if (!user.isTeacher()) {
+      return
+    }
+    const resp = api.useSubmissions()
+    resp.then(subs => {
+      setSubmissions(subs || [])
+    })
+  }, [user.id])
+
+  const remove = (s: Submission) => () => {
+    setSubmissions(submissions.filter(other => other !== s))
+  }
+
+  return (
+    <>
+      <OnlyTeacher>
+        <div>
+          <h2 className="text-2xl mb-5">Waiting for Review</h2>
+          {submissions.length === 0 && <p>Nothing to review</p>}
+          {submissions.map(s => <div key={`${s.studentId}_${s.achievementId}`} data-testid={`${s.achievementName}_submission`}>
+              <SubmissionRow submission={s} onReviewed={remove(s)}/>
+            </div>
+          )}
+        </div>
+      </OnlyTeacher>
+    </>
+  );
+}
+
+export default render
@@ -0,0 +1,17 @@
+import client from "../../app/api/client";
+
+const reviewSubmission = async (approve: boolean, id: String, achievement: String, comment: String): Promise<Boolean> => {
+  try {
+    await client.post(`/students/${id}/achievements/${achievement}/${approve ? "approve" : "reject"}`,
+      {
+        "comment": comment
+      }
+    )
+  } catch (e) {
+    console.log(e)
+    return false
+  }
+  return true
+}
+
+export default {reviewSubmission}
\ No newline at end of file
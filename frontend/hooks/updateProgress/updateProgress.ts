@@ -1,9 +1,9 @@
-import axios from "axios";
+import client from "../../app/api/client";
 import {Progress} from "../../app/achievement/achievement";
 
 const updateProgress = async (p: Progress, id: String, achievement: String): Promise<Boolean> => {
   try {
-    await axios.put(`http://localhost:4000/students/${id}/achievements/${achievement}/progress`,
+    await client.put(`/students/${id}/achievements/${achievement}/progress`,
       {
         "progress": p
       }
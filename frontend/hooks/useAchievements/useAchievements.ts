@@ -1,10 +1,10 @@
 import {Achievement} from "../../app/achievement/achievement";
-import axios from "axios";
+import client from "../../app/api/client";
 
 const useAchievements = async (studentId: string): Promise<Achievement[] | undefined> => {
   let resp: any
   try {
-    resp = await axios.get(`http://localhost:4000/students/${studentId}/achievements`)
+    resp = await client.get(`/students/${studentId}/achievements`)
   } catch (e) {
     console.log(e)
     return undefined
@@ -1,14 +1,15 @@
-import axios from "axios";
+import client, {saveToken} from "../../app/api/client";
 import {User, IsStudent, IsTeacher} from "../../app/user/auth";
 
 const useLogin = async (code: string): Promise<User | undefined> => {
   let resp: any
   try {
-    resp = await axios.post("http://localhost:4000/login", {code})
+    resp = await client.post("/login", {code})
   } catch (e) {
     console.log(e)
     return undefined
   }
+  saveToken(resp.data.token)
   return {
     isStudent(): boolean {
       return IsStudent(resp.data.type);
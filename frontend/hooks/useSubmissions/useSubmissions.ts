@@ -0,0 +1,21 @@
+import {Submission} from "../../app/achievement/achievement";
+import client from "../../app/api/client";
+
+const useSubmissions = async (): Promise<Submission[] | undefined> => {
+  let resp: any
+  try {
+    resp = await client.get("/submissions")
+  } catch (e) {
+    console.log(e)
+    return undefined
+  }
+  return resp.data.submissions.map(s => ({
+    studentId: s.student.id,
+    studentName: s.student.name,
+    achievementId: s.achievement.id,
+    achievementName: s.achievement.name,
+    submittedAt: s.submittedAt,
+  }))
+}
+
+export default {useSubmissions}
\ No newline at end of file
@@ -1,6 +1,7 @@
 import Head from "next/head"
 import auth from "../../app/user/auth"
 import AchievementList from "../../components/AchievementList/AchievementList";
+import SubmissionList from "../../components/SubmissionList/SubmissionList";
 import Header from "../../components/Header/Header";
 
 const {OnlyAuth} = auth
@@ -18,6 +19,7 @@ export default function Home() {
           <div className="max-w-5xl text-center">
             <h1 className="text-3xl mb-10">My Achievement Progress</h1>
             <AchievementList/>
+            <SubmissionList/>
           </div>
         </div>
       </OnlyAuth>
@@ -6,14 +6,17 @@ type MockUserOpts = {
   name?: string
 }
 
-export const mockUser = (opts?: MockUserOpts): User => ({
-  id: opts?.id || "p1000",
-  type: opts?.type || "Student",
-  name: opts?.name || "mock user",
-  isTeacher(): boolean {
-    return false
-  },
-  isStudent(): boolean {
-    return true
+export const mockUser = (opts?: MockUserOpts): User => {
+  const type = opts?.type || "Student"
+  return {
+    id: opts?.id || "p1000",
+    type,
+    name: opts?.name || "mock user",
+    isTeacher(): boolean {
+      return type === "Teacher"
+    },
+    isStudent(): boolean {
+      return type === "Student"
+    }
   }
-});
+};